@@ -0,0 +1,207 @@
+/*
+ * allocate.go - proportional allocation
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "math/bits"
+    "sort"
+    "strconv"
+)
+
+// no parts or all ratios are zero
+var ErrAllocation = errors.New("godec64: invalid allocation ratios")
+
+// assignment of leftover units (units remaining after rounding parts down)
+type LeftoverMode uint8
+
+const (
+    // give leftover units to parts with largest fractional remainder
+    // (largest remainder method), ties are given to earlier parts
+    LeftoverLargestFraction LeftoverMode = iota
+    // give leftover units to first parts with fractional remainder
+    LeftoverFirst
+    // give leftover units to last parts with fractional remainder
+    LeftoverLast
+)
+
+// give leftover units to parts by mode. rems are fractional remainders
+// (numerators with common denominator), leftover is not greater than number
+// of non-zero remainders
+func assignLeftover(parts, rems []uint64, leftover uint64, mode LeftoverMode) {
+    switch mode {
+    case LeftoverFirst:
+        for i := 0; leftover > 0; i++ {
+            if rems[i]!=0 {
+                parts[i]++
+                leftover--
+            }
+        }
+    case LeftoverLast:
+        for i := len(parts)-1; leftover > 0; i-- {
+            if rems[i]!=0 {
+                parts[i]++
+                leftover--
+            }
+        }
+    default:
+        order := make([]int, len(parts))
+        for i := range order {
+            order[i] = i
+        }
+        sort.SliceStable(order, func(i, j int) bool {
+            return rems[order[i]] > rems[order[j]]
+        })
+        for _, i := range order[:leftover] {
+            parts[i]++
+        }
+    }
+}
+
+// allocate number with precision proportionally to ratios, sum of parts is
+// exactly number. Returns ErrAllocation if ratios are empty or all zero or
+// strconv.ErrRange if sum of ratios is too big
+func (a UDec64) Allocate(ratios []UDec64, precision uint,
+                         mode LeftoverMode) ([]UDec64, error) {
+    var sum uint64
+    for _, r := range ratios {
+        var carry uint64
+        sum, carry = bits.Add64(sum, uint64(r), 0)
+        if carry!=0 { return nil, strconv.ErrRange }
+    }
+    if sum==0 { return nil, ErrAllocation }
+    parts := make([]uint64, len(ratios))
+    rems := make([]uint64, len(ratios))
+    leftover := uint64(a)
+    for i, r := range ratios {
+        // a*r/sum, a*r < 2^64*sum
+        hi, lo := bits.Mul64(uint64(a), uint64(r))
+        parts[i], rems[i] = bits.Div64(hi, lo, sum)
+        leftover -= parts[i]
+    }
+    assignLeftover(parts, rems, leftover, mode)
+    result := make([]UDec64, len(parts))
+    for i, p := range parts {
+        result[i] = UDec64(p)
+    }
+    return result, nil
+}
+
+// split number with precision into n parts differing by at most one unit,
+// sum of parts is exactly number. Returns ErrAllocation if n is not positive
+func (a UDec64) SplitEvenly(n int, precision uint,
+                            mode LeftoverMode) ([]UDec64, error) {
+    if n <= 0 { return nil, ErrAllocation }
+    q, leftover := uint64(a)/uint64(n), uint64(a)%uint64(n)
+    parts := make([]uint64, n)
+    rems := make([]uint64, n)
+    for i := range parts {
+        parts[i], rems[i] = q, leftover
+    }
+    assignLeftover(parts, rems, leftover, mode)
+    result := make([]UDec64, n)
+    for i, p := range parts {
+        result[i] = UDec64(p)
+    }
+    return result, nil
+}
+
+// convert allocated parts to money with sign of m
+func (m Money) moneyParts(parts []UDec64) []Money {
+    result := make([]Money, len(parts))
+    for i, p := range parts {
+        result[i] = Money{ p, m.negative && p!=0, m.precision, m.currency }
+    }
+    return result
+}
+
+// allocate money proportionally to ratios in minor units of currency.
+// Sum of parts is exactly m
+func (m Money) Allocate(ratios []UDec64, mode LeftoverMode) ([]Money, error) {
+    parts, err := m.amount.Allocate(ratios, uint(m.precision), mode)
+    if err!=nil { return nil, err }
+    return m.moneyParts(parts), nil
+}
+
+// split money into n equal parts in minor units of currency.
+// Sum of parts is exactly m
+func (m Money) SplitEvenly(n int, mode LeftoverMode) ([]Money, error) {
+    parts, err := m.amount.SplitEvenly(n, uint(m.precision), mode)
+    if err!=nil { return nil, err }
+    return m.moneyParts(parts), nil
+}
+
+// round values from srcPrec to dstPrec with rounding mode applied to sum:
+// sum of rounded values is equal to rounded sum of values. Values are rounded
+// down and leftover units are given to values with largest rounding remainder
+// (ties are given to earlier values). Returns strconv.ErrRange if result
+// does not fit in 64 bits
+func RoundPreservingSumR(values []UDec64, srcPrec, dstPrec uint,
+                         mode RoundingMode) ([]UDec64, error) {
+    result := make([]UDec64, len(values))
+    if dstPrec >= srcPrec {
+        for i, v := range values {
+            r, ok := mulPow10(uint64(v), dstPrec-srcPrec)
+            if !ok { return nil, strconv.ErrRange }
+            result[i] = UDec64(r)
+        }
+        return result, nil
+    }
+    var sumHi, sumLo uint64
+    for _, v := range values {
+        var carry uint64
+        sumLo, carry = bits.Add64(sumLo, uint64(v), 0)
+        sumHi += carry
+    }
+    total, ok := div128Pow10(sumHi, sumLo, srcPrec-dstPrec, mode)
+    if !ok { return nil, strconv.ErrRange }
+    // divisor 10^k, zero if it does not fit in 64 bits (all values are lower)
+    var d uint64
+    if k := srcPrec-dstPrec; k < uint(len(uint64_powers)) {
+        d = uint64_powers[k]
+    } else if k==19 {
+        d = 10000000000000000000
+    }
+    parts := make([]uint64, len(values))
+    rems := make([]uint64, len(values))
+    leftover := total
+    for i, v := range values {
+        if d!=0 {
+            parts[i], rems[i] = uint64(v)/d, uint64(v)%d
+        } else {
+            rems[i] = uint64(v)
+        }
+        leftover -= parts[i]
+    }
+    assignLeftover(parts, rems, leftover, LeftoverLargestFraction)
+    for i, p := range parts {
+        result[i] = UDec64(p)
+    }
+    return result, nil
+}
+
+// round values from srcPrec to dstPrec preserving rounded sum (half up as in
+// Convert), for example percentages of pie chart still sum to 100.00
+func RoundPreservingSum(values []UDec64, srcPrec, dstPrec uint) ([]UDec64, error) {
+    return RoundPreservingSumR(values, srcPrec, dstPrec, RoundHalfUp)
+}
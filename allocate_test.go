@@ -0,0 +1,276 @@
+/*
+ * allocate_test.go - proportional allocation tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "reflect"
+    "strconv"
+    "testing"
+)
+
+type AllocateTC struct {
+    a UDec64
+    ratios []UDec64
+    mode LeftoverMode
+    expected []UDec64
+    expError error
+}
+
+func TestUDec64Allocate(t *testing.T) {
+    testCases := []AllocateTC {
+        AllocateTC{ 10000, []UDec64{ 1, 1, 1 }, LeftoverLargestFraction,
+                []UDec64{ 3334, 3333, 3333 }, nil },
+        AllocateTC{ 10000, []UDec64{ 1, 1, 1 }, LeftoverFirst,
+                []UDec64{ 3334, 3333, 3333 }, nil },
+        AllocateTC{ 10000, []UDec64{ 1, 1, 1 }, LeftoverLast,
+                []UDec64{ 3333, 3333, 3334 }, nil },
+        AllocateTC{ 10000, []UDec64{ 50, 30, 20 }, LeftoverLargestFraction,
+                []UDec64{ 5000, 3000, 2000 }, nil },
+        AllocateTC{ 5, []UDec64{ 70, 30 }, LeftoverLargestFraction,
+                []UDec64{ 4, 1 }, nil },
+        AllocateTC{ 5, []UDec64{ 70, 30 }, LeftoverLast,
+                []UDec64{ 3, 2 }, nil },
+        // 1/6, 2/6, 3/6 of 100: 16.67, 33.33, 50
+        AllocateTC{ 100, []UDec64{ 1, 2, 3 }, LeftoverLargestFraction,
+                []UDec64{ 17, 33, 50 }, nil },
+        AllocateTC{ 100, []UDec64{ 1, 2, 3 }, LeftoverFirst,
+                []UDec64{ 17, 33, 50 }, nil },
+        AllocateTC{ 100, []UDec64{ 1, 2, 3 }, LeftoverLast,
+                []UDec64{ 16, 34, 50 }, nil },
+        // 7 by 0.35, 0.35, 0.30: 2.45, 2.45, 2.1
+        AllocateTC{ 7, []UDec64{ 35, 35, 30 }, LeftoverLargestFraction,
+                []UDec64{ 3, 2, 2 }, nil },
+        AllocateTC{ 7, []UDec64{ 35, 35, 30 }, LeftoverLast,
+                []UDec64{ 2, 2, 3 }, nil },
+        // zero ratio gets nothing
+        AllocateTC{ 10, []UDec64{ 0, 1, 1, 1 }, LeftoverFirst,
+                []UDec64{ 0, 4, 3, 3 }, nil },
+        AllocateTC{ 10, []UDec64{ 1, 1, 1, 0 }, LeftoverLast,
+                []UDec64{ 3, 3, 4, 0 }, nil },
+        AllocateTC{ 0, []UDec64{ 1, 2 }, LeftoverFirst, []UDec64{ 0, 0 }, nil },
+        AllocateTC{ 18446744073709551615, []UDec64{ 1, 1 }, LeftoverFirst,
+                []UDec64{ 9223372036854775808, 9223372036854775807 }, nil },
+        AllocateTC{ 18446744073709551615, []UDec64{ 18446744073709551614, 1 },
+                LeftoverLargestFraction,
+                []UDec64{ 18446744073709551614, 1 }, nil },
+        AllocateTC{ 100, []UDec64{}, LeftoverFirst, nil, ErrAllocation },
+        AllocateTC{ 100, []UDec64{ 0, 0 }, LeftoverFirst, nil, ErrAllocation },
+        AllocateTC{ 100, []UDec64{ 18446744073709551615, 1 }, LeftoverFirst,
+                nil, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        result, err := tc.a.Allocate(tc.ratios, 2, tc.mode)
+        if !reflect.DeepEqual(tc.expected, result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: allocate(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.ratios, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+type SplitEvenlyTC struct {
+    a UDec64
+    n int
+    mode LeftoverMode
+    expected []UDec64
+    expError error
+}
+
+func TestUDec64SplitEvenly(t *testing.T) {
+    testCases := []SplitEvenlyTC {
+        SplitEvenlyTC{ 10000, 3, LeftoverLargestFraction,
+                []UDec64{ 3334, 3333, 3333 }, nil },
+        SplitEvenlyTC{ 10000, 3, LeftoverLast, []UDec64{ 3333, 3333, 3334 }, nil },
+        SplitEvenlyTC{ 10001, 3, LeftoverFirst, []UDec64{ 3334, 3334, 3333 }, nil },
+        SplitEvenlyTC{ 10001, 3, LeftoverLast, []UDec64{ 3333, 3334, 3334 }, nil },
+        SplitEvenlyTC{ 9, 3, LeftoverLast, []UDec64{ 3, 3, 3 }, nil },
+        SplitEvenlyTC{ 2, 4, LeftoverFirst, []UDec64{ 1, 1, 0, 0 }, nil },
+        SplitEvenlyTC{ 5, 1, LeftoverFirst, []UDec64{ 5 }, nil },
+        SplitEvenlyTC{ 5, 0, LeftoverFirst, nil, ErrAllocation },
+        SplitEvenlyTC{ 5, -1, LeftoverFirst, nil, ErrAllocation },
+    }
+    for i, tc := range testCases {
+        result, err := tc.a.SplitEvenly(tc.n, 2, tc.mode)
+        if !reflect.DeepEqual(tc.expected, result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: split(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.n, tc.mode, tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestUDec64AllocateSum(t *testing.T) {
+    ratios := []UDec64{ 13, 7, 29, 1, 0, 50 }
+    for a := UDec64(0); a < 2000; a += 7 {
+        for _, mode := range []LeftoverMode{ LeftoverLargestFraction,
+                    LeftoverFirst, LeftoverLast } {
+            parts, err := a.Allocate(ratios, 2, mode)
+            if err!=nil {
+                t.Fatalf("Result mismatch: allocate(%v): %v", a, err)
+            }
+            var sum UDec64
+            for i, p := range parts {
+                sum += p
+                // less than one unit from exact share
+                exact := float64(a)*float64(ratios[i])/100
+                if d := float64(p)-exact; d <= -1 || d >= 1 {
+                    t.Errorf("Result mismatch: allocate(%v,%v): part %d: %v",
+                             a, mode, i, p)
+                }
+            }
+            if sum!=a {
+                t.Errorf("Result mismatch: allocate(%v,%v): sum %v", a, mode, sum)
+            }
+        }
+    }
+}
+
+func TestMoneyAllocate(t *testing.T) {
+    m := mustParseMoney(t, "-100.00 USD")
+    parts, err := m.SplitEvenly(3, LeftoverLargestFraction)
+    if err!=nil {
+        t.Fatalf("split: %v", err)
+    }
+    expected := []string{ "-33.34 USD", "-33.33 USD", "-33.33 USD" }
+    for i, p := range parts {
+        if p.String()!=expected[i] {
+            t.Errorf("Result mismatch: split: %d: %v!=%v", i, expected[i], p)
+        }
+    }
+    parts, err = mustParseMoney(t, "1000 JPY").Allocate([]UDec64{ 1, 0, 2 },
+                                                        LeftoverLast)
+    if err!=nil {
+        t.Fatalf("allocate: %v", err)
+    }
+    expected = []string{ "333 JPY", "0 JPY", "667 JPY" }
+    for i, p := range parts {
+        if p.String()!=expected[i] {
+            t.Errorf("Result mismatch: allocate: %d: %v!=%v", i, expected[i], p)
+        }
+    }
+    if _, err := m.Allocate(nil, LeftoverFirst); err!=ErrAllocation {
+        t.Errorf("Result mismatch: allocate(nil)->%v", err)
+    }
+}
+
+type RoundPreservingSumTC struct {
+    values []UDec64
+    srcPrec, dstPrec uint
+    mode RoundingMode
+    expected []UDec64
+    expError error
+}
+
+func TestRoundPreservingSum(t *testing.T) {
+    testCases := []RoundPreservingSumTC {
+        // 33.3333 * 3 = 99.9999 -> 100.00
+        RoundPreservingSumTC{ []UDec64{ 333333, 333333, 333333 }, 4, 2, RoundHalfUp,
+                []UDec64{ 3334, 3333, 3333 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 333333, 333333, 333333 }, 4, 2, RoundDown,
+                []UDec64{ 3333, 3333, 3333 }, nil },
+        // 1.004 + 1.004 + 1.004 = 3.012 -> 3.01
+        RoundPreservingSumTC{ []UDec64{ 1004, 1004, 1004 }, 3, 2, RoundHalfUp,
+                []UDec64{ 101, 100, 100 }, nil },
+        // largest remainder gets adjusted: 0.15 + 0.26 + 0.59 = 1.00
+        RoundPreservingSumTC{ []UDec64{ 15, 26, 59 }, 2, 1, RoundHalfUp,
+                []UDec64{ 1, 3, 6 }, nil },
+        // 12.5% + 12.5% + 75% -> 13 + 12 + 75
+        RoundPreservingSumTC{ []UDec64{ 125, 125, 750 }, 1, 0, RoundHalfEven,
+                []UDec64{ 13, 12, 75 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 125, 125, 751 }, 1, 0, RoundHalfEven,
+                []UDec64{ 13, 12, 75 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 125, 125, 759 }, 1, 0, RoundHalfEven,
+                []UDec64{ 13, 12, 76 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 15, 24 }, 1, 0, RoundUp,
+                []UDec64{ 2, 2 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 5, 5, 5 }, 1, 0, RoundHalfUp,
+                []UDec64{ 1, 1, 0 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 0, 0 }, 4, 2, RoundHalfUp,
+                []UDec64{ 0, 0 }, nil },
+        RoundPreservingSumTC{ []UDec64{}, 4, 2, RoundHalfUp, []UDec64{}, nil },
+        // difference of precisions greater than 18
+        RoundPreservingSumTC{ []UDec64{ 5, 7 }, 20, 0, RoundHalfUp,
+                []UDec64{ 0, 0 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 5, 7 }, 20, 0, RoundUp,
+                []UDec64{ 0, 1 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 5000000000000000000, 6000000000000000000 },
+                19, 0, RoundHalfUp, []UDec64{ 0, 1 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 12000000000000000000, 3000000000000000000 },
+                19, 0, RoundHalfUp, []UDec64{ 1, 1 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 12000000000000000000, 9000000000000000000 },
+                19, 0, RoundDown, []UDec64{ 1, 1 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 12000000000000000000, 9000000000000000000 },
+                21, 1, RoundHalfUp, []UDec64{ 0, 0 }, nil },
+        // same or greater precision
+        RoundPreservingSumTC{ []UDec64{ 1, 22 }, 2, 2, RoundHalfUp,
+                []UDec64{ 1, 22 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 1, 22 }, 2, 4, RoundHalfUp,
+                []UDec64{ 100, 2200 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 1, 18446744073709551615 }, 2, 4, RoundHalfUp,
+                nil, strconv.ErrRange },
+        // sum greater than 64 bits
+        RoundPreservingSumTC{ []UDec64{ 18446744073709551615, 18446744073709551615 },
+                1, 0, RoundHalfUp,
+                []UDec64{ 1844674407370955162, 1844674407370955161 }, nil },
+        RoundPreservingSumTC{ []UDec64{ 18446744073709551615, 18446744073709551615 },
+                0, 0, RoundHalfUp,
+                []UDec64{ 18446744073709551615, 18446744073709551615 }, nil },
+    }
+    for i, tc := range testCases {
+        result, err := RoundPreservingSumR(tc.values, tc.srcPrec, tc.dstPrec, tc.mode)
+        if !reflect.DeepEqual(tc.expected, result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: round(%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.values, tc.srcPrec, tc.dstPrec, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+        if tc.mode!=RoundHalfUp { continue }
+        result, err = RoundPreservingSum(tc.values, tc.srcPrec, tc.dstPrec)
+        if !reflect.DeepEqual(tc.expected, result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: round(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.values, tc.srcPrec, tc.dstPrec,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestRoundPreservingSumTotal(t *testing.T) {
+    values := make([]UDec64, 0, 20)
+    for k := UDec64(1); k <= 20; k++ {
+        values = append(values, k*k*7919 % 100003)
+        result, err := RoundPreservingSum(values, 5, 2)
+        if err!=nil {
+            t.Fatalf("Result mismatch: %d: %v", k, err)
+        }
+        var sum, roundedSum UDec64
+        for i, v := range values {
+            sum += v
+            roundedSum += result[i]
+            // less than one unit from exact value
+            if d := int64(result[i])*1000-int64(v); d <= -1000 || d >= 1000 {
+                t.Errorf("Result mismatch: %d: value %d: %v->%v", k, i, v, result[i])
+            }
+        }
+        if sum.Convert(5, 2, true)!=roundedSum {
+            t.Errorf("Result mismatch: %d: sum %v!=%v", k, sum.Convert(5, 2, true),
+                     roundedSum)
+        }
+    }
+}
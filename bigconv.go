@@ -0,0 +1,91 @@
+/*
+ * bigconv.go - conversion between UDec64 and math/big types
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "math/big"
+    "math/bits"
+    "strconv"
+)
+
+// convert to big.Int (fraction part is truncated)
+func (a UDec64) ToBigInt(precision uint) *big.Int {
+    return new(big.Int).SetUint64(uint64(a) / uint64_powers[precision])
+}
+
+// convert to big.Rat (exact value)
+func (a UDec64) ToBigRat(precision uint) *big.Rat {
+    return new(big.Rat).SetFrac(new(big.Int).SetUint64(uint64(a)),
+                    new(big.Int).SetUint64(uint64_powers[precision]))
+}
+
+// convert to big.Float with floatPrec bits of mantissa (if zero then 64 bits).
+// Value is rounded to nearest even
+func (a UDec64) ToBigFloat(precision uint, floatPrec uint) *big.Float {
+    if floatPrec==0 { floatPrec = 64 }
+    z := new(big.Float).SetPrec(floatPrec)
+    return z.Quo(new(big.Float).SetUint64(uint64(a)),
+                 new(big.Float).SetUint64(uint64_powers[precision]))
+}
+
+// convert big.Int to UDec64 with precision
+func FromBigInt(x *big.Int, precision uint) (UDec64, error) {
+    if x.Sign() < 0 || !x.IsUint64() {
+        return 0, strconv.ErrRange
+    }
+    hi, lo := bits.Mul64(x.Uint64(), uint64_powers[precision])
+    if hi!=0 {
+        return 0, strconv.ErrRange
+    }
+    return UDec64(lo), nil
+}
+
+// convert big.Rat to UDec64 with precision and rounding mode
+func FromBigRat(x *big.Rat, precision uint, mode RoundingMode) (UDec64, error) {
+    if x.Sign() < 0 {
+        return 0, strconv.ErrRange
+    }
+    num := new(big.Int).SetUint64(uint64_powers[precision])
+    num.Mul(num, x.Num())
+    q, r := num.QuoRem(num, x.Denom(), new(big.Int))
+    if !q.IsUint64() {
+        return 0, strconv.ErrRange
+    }
+    v := q.Uint64()
+    exact := r.Sign()==0
+    half := r.Lsh(r, 1).Cmp(x.Denom())
+    if roundUp(v, half, exact, mode) {
+        v++
+        if v==0 { return 0, strconv.ErrRange }
+    }
+    return UDec64(v), nil
+}
+
+// convert big.Float to UDec64 with precision and rounding mode
+func FromBigFloat(x *big.Float, precision uint, mode RoundingMode) (UDec64, error) {
+    if x.IsInf() || x.Sign() < 0 {
+        return 0, strconv.ErrRange
+    }
+    r, _ := x.Rat(nil)
+    return FromBigRat(r, precision, mode)
+}
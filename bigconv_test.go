@@ -0,0 +1,151 @@
+/*
+ * bigconv_test.go - conversion between UDec64 and math/big types tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "math/big"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToBigTC struct {
+    value UDec64
+    precision uint
+    expInt string
+    expRat string
+    expFloat string
+}
+
+func TestUDec64ToBig(t *testing.T) {
+    testCases := []UDec64ToBigTC {
+        UDec64ToBigTC{ 0, 5, "0", "0/1", "0" },
+        UDec64ToBigTC{ 1234567, 3, "1234", "1234567/1000", "1234.567" },
+        UDec64ToBigTC{ 1999, 3, "1", "1999/1000", "1.999" },
+        UDec64ToBigTC{ 25, 2, "0", "1/4", "0.25" },
+        UDec64ToBigTC{ 0xffffffffffffffff, 0, "18446744073709551615",
+                "18446744073709551615/1", "18446744073709551615" },
+        UDec64ToBigTC{ 0xffffffffffffffff, 18, "18",
+                "3689348814741910323/200000000000000000", "18.446744073709551615" },
+    }
+    for i, tc := range testCases {
+        resInt := tc.value.ToBigInt(tc.precision).String()
+        if tc.expInt!=resInt {
+            t.Errorf("Result mismatch: %d: tobigint(%v,%v)->%v!=%v",
+                     i, tc.value, tc.precision, tc.expInt, resInt)
+        }
+        resRat := tc.value.ToBigRat(tc.precision).String()
+        if tc.expRat!=resRat {
+            t.Errorf("Result mismatch: %d: tobigrat(%v,%v)->%v!=%v",
+                     i, tc.value, tc.precision, tc.expRat, resRat)
+        }
+        resFloat := tc.value.ToBigFloat(tc.precision, 128).Text('f', -1)
+        if tc.expFloat!=resFloat {
+            t.Errorf("Result mismatch: %d: tobigfloat(%v,%v)->%v!=%v",
+                     i, tc.value, tc.precision, tc.expFloat, resFloat)
+        }
+    }
+    // default precision of big.Float
+    f := UDec64(1).ToBigFloat(1, 0)
+    if f.Prec()!=64 {
+        t.Errorf("Precision mismatch: %v!=64", f.Prec())
+    }
+}
+
+type FromBigTC struct {
+    value string
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestFromBigInt(t *testing.T) {
+    testCases := []FromBigTC {
+        FromBigTC{ "0", 5, RoundDown, 0, nil },
+        FromBigTC{ "1234", 3, RoundDown, 1234000, nil },
+        FromBigTC{ "18446744073709551615", 0, RoundDown, 0xffffffffffffffff, nil },
+        FromBigTC{ "18446744073709551616", 0, RoundDown, 0, strconv.ErrRange },
+        FromBigTC{ "18446744073709551615", 1, RoundDown, 0, strconv.ErrRange },
+        FromBigTC{ "-1", 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        x, _ := new(big.Int).SetString(tc.value, 10)
+        result, err := FromBigInt(x, tc.precision)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: frombigint(%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestFromBigRat(t *testing.T) {
+    testCases := []FromBigTC {
+        FromBigTC{ "0", 5, RoundDown, 0, nil },
+        FromBigTC{ "1/3", 5, RoundDown, 33333, nil },
+        FromBigTC{ "1/3", 5, RoundUp, 33334, nil },
+        FromBigTC{ "2/3", 5, RoundDown, 66666, nil },
+        FromBigTC{ "2/3", 5, RoundHalfUp, 66667, nil },
+        FromBigTC{ "1/8", 2, RoundHalfUp, 13, nil },
+        FromBigTC{ "1/8", 2, RoundHalfDown, 12, nil },
+        FromBigTC{ "1/8", 2, RoundHalfEven, 12, nil },
+        FromBigTC{ "3/8", 2, RoundHalfEven, 38, nil },
+        FromBigTC{ "1234567/1000", 3, RoundDown, 1234567, nil },
+        FromBigTC{ "1234567/1000", 1, RoundHalfUp, 12346, nil },
+        FromBigTC{ "18446744073709551615/10", 1, RoundDown, 0xffffffffffffffff, nil },
+        FromBigTC{ "184467440737095516155/100", 1, RoundDown, 0xffffffffffffffff, nil },
+        FromBigTC{ "184467440737095516155/100", 1, RoundHalfUp,
+                0, strconv.ErrRange },
+        FromBigTC{ "18446744073709551616", 0, RoundDown, 0, strconv.ErrRange },
+        FromBigTC{ "-1/3", 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        x, _ := new(big.Rat).SetString(tc.value)
+        result, err := FromBigRat(x, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: frombigrat(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+func TestFromBigFloat(t *testing.T) {
+    testCases := []FromBigTC {
+        FromBigTC{ "0", 5, RoundDown, 0, nil },
+        FromBigTC{ "0.125", 2, RoundHalfEven, 12, nil },
+        FromBigTC{ "0.125", 2, RoundHalfUp, 13, nil },
+        FromBigTC{ "1234.5678", 2, RoundHalfUp, 123457, nil },
+        FromBigTC{ "1234.5678", 2, RoundDown, 123456, nil },
+        FromBigTC{ "1e20", 0, RoundDown, 0, strconv.ErrRange },
+        FromBigTC{ "-0.5", 0, RoundDown, 0, strconv.ErrRange },
+        FromBigTC{ "+Inf", 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        x, _, _ := new(big.Float).SetPrec(200).Parse(tc.value, 10)
+        result, err := FromBigFloat(x, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: frombigfloat(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
@@ -0,0 +1,243 @@
+/*
+ * cobol.go - COBOL packed decimal (COMP-3) and zoned decimal formats
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "strconv"
+)
+
+var (
+    // invalid COBOL picture
+    ErrCobolPic = errors.New("godec64: invalid COBOL picture")
+    // invalid digit nibble or zone
+    ErrCobolDigit = errors.New("godec64: invalid COBOL digit")
+    // invalid sign nibble or zone
+    ErrCobolSign = errors.New("godec64: invalid COBOL sign")
+)
+
+// error of decoding COBOL packed or zoned decimal
+type CobolError struct {
+    // offset of invalid byte
+    Offset int
+    // invalid byte
+    Byte byte
+    // ErrCobolDigit or ErrCobolSign
+    Err error
+}
+
+func (e *CobolError) Error() string {
+    return e.Err.Error() + " at offset " + strconv.Itoa(e.Offset) +
+            " (byte 0x" + strconv.FormatUint(uint64(e.Byte), 16) + ")"
+}
+
+func (e *CobolError) Unwrap() error {
+    return e.Err
+}
+
+const cobolMaxDigits = 38
+
+// COBOL numeric picture: PIC S9(IntDigits)V9(FracDigits)
+type CobolPic struct {
+    // number of digits before implied decimal point
+    IntDigits uint
+    // number of digits after implied decimal point
+    FracDigits uint
+    // signed picture (with S)
+    Signed bool
+}
+
+// get total number of digits
+func (pic CobolPic) Digits() uint {
+    return pic.IntDigits+pic.FracDigits
+}
+
+// get size in bytes of packed decimal (COMP-3)
+func (pic CobolPic) PackedSize() int {
+    return int(pic.Digits())/2+1
+}
+
+// get size in bytes of zoned decimal
+func (pic CobolPic) ZonedSize() int {
+    return int(pic.Digits())
+}
+
+func (pic CobolPic) check() error {
+    if pic.Digits()==0 || pic.Digits() > cobolMaxDigits {
+        return ErrCobolPic
+    }
+    return nil
+}
+
+// get digits of value in picture
+func (a UDec64) cobolDigits(digits []byte, precision uint, pic CobolPic,
+                            mode RoundingMode) error {
+    v := uint64(a)
+    if pic.FracDigits < precision {
+        v = divPow10(v, precision-pic.FracDigits, mode)
+        precision = pic.FracDigits
+    }
+    if !fixedDigits(digits, v, precision, int(pic.IntDigits)) {
+        return strconv.ErrRange
+    }
+    return nil
+}
+
+// append COBOL packed decimal (COMP-3) to dst. Value is rounded if precision
+// is greater than number of fraction digits of picture. Sign nibble is 0xC
+// for signed picture and 0xF for unsigned picture
+func (a UDec64) AppendCobolPacked(dst []byte, precision uint, pic CobolPic,
+                                  mode RoundingMode) ([]byte, error) {
+    if err := pic.check(); err!=nil {
+        return dst, err
+    }
+    var dbuf [cobolMaxDigits+1]byte
+    n := int(pic.Digits())
+    pad := 1-n&1 // leading zero nibble if number of digits is even
+    digits := dbuf[:pad+n]
+    if err := a.cobolDigits(digits[pad:], precision, pic, mode); err!=nil {
+        return dst, err
+    }
+    sign := byte(0xf)
+    if pic.Signed { sign = 0xc }
+    for i:=0; i+1 < len(digits); i += 2 {
+        dst = append(dst, digits[i]<<4 | digits[i+1])
+    }
+    return append(dst, digits[len(digits)-1]<<4 | sign), nil
+}
+
+// convert to COBOL packed decimal (COMP-3)
+func (a UDec64) ToCobolPacked(precision uint, pic CobolPic,
+                              mode RoundingMode) ([]byte, error) {
+    return a.AppendCobolPacked(nil, precision, pic, mode)
+}
+
+// convert COBOL packed decimal (COMP-3) to UDec64 with precision and
+// rounding mode. Invalid nibbles are reported as *CobolError, negative
+// values as strconv.ErrRange
+func CobolPackedToUDec64(b []byte, pic CobolPic, precision uint,
+                         mode RoundingMode) (UDec64, error) {
+    if err := pic.check(); err!=nil {
+        return 0, err
+    }
+    if len(b)!=pic.PackedSize() {
+        return 0, strconv.ErrSyntax
+    }
+    var dbuf [cobolMaxDigits+1]byte
+    digits := dbuf[:0]
+    for i, c := range b {
+        if c>>4 > 9 || (i+1 < len(b) && c&15 > 9) {
+            return 0, &CobolError{ i, c, ErrCobolDigit }
+        }
+        digits = append(digits, c>>4)
+        if i+1 < len(b) {
+            digits = append(digits, c&15)
+        }
+    }
+    last := len(b)-1
+    neg := false
+    switch b[last]&15 {
+    case 0xa, 0xc, 0xe, 0xf:
+    case 0xb, 0xd:
+        neg = true
+    default:
+        return 0, &CobolError{ last, b[last], ErrCobolSign }
+    }
+    if int(pic.Digits())&1==0 && digits[0]!=0 {
+        return 0, &CobolError{ 0, b[0], ErrCobolDigit } // non-zero pad nibble
+    }
+    return cobolDigitsToUDec64(digits, neg, pic, precision, mode)
+}
+
+func cobolDigitsToUDec64(digits []byte, neg bool, pic CobolPic,
+                         precision uint, mode RoundingMode) (UDec64, error) {
+    if neg {
+        for _, d := range digits {
+            if d!=0 {
+                return 0, strconv.ErrRange // negative
+            }
+        }
+        return 0, nil
+    }
+    return digitsToUDec64(digits, -int(pic.FracDigits), precision, mode)
+}
+
+// append COBOL zoned decimal (EBCDIC, DISPLAY) to dst. Value is rounded if
+// precision is greater than number of fraction digits of picture. Zone of
+// last digit is 0xC (sign overpunch) for signed picture, otherwise 0xF
+func (a UDec64) AppendCobolZoned(dst []byte, precision uint, pic CobolPic,
+                                 mode RoundingMode) ([]byte, error) {
+    if err := pic.check(); err!=nil {
+        return dst, err
+    }
+    var dbuf [cobolMaxDigits]byte
+    digits := dbuf[:pic.Digits()]
+    if err := a.cobolDigits(digits, precision, pic, mode); err!=nil {
+        return dst, err
+    }
+    last := len(digits)-1
+    for _, d := range digits[:last] {
+        dst = append(dst, 0xf0 | d)
+    }
+    if pic.Signed {
+        return append(dst, 0xc0 | digits[last]), nil
+    }
+    return append(dst, 0xf0 | digits[last]), nil
+}
+
+// convert to COBOL zoned decimal (EBCDIC, DISPLAY)
+func (a UDec64) ToCobolZoned(precision uint, pic CobolPic,
+                             mode RoundingMode) ([]byte, error) {
+    return a.AppendCobolZoned(nil, precision, pic, mode)
+}
+
+// convert COBOL zoned decimal (EBCDIC, DISPLAY) with overpunched sign in zone
+// of last digit to UDec64 with precision and rounding mode. Invalid zones and
+// digits are reported as *CobolError, negative values as strconv.ErrRange
+func CobolZonedToUDec64(b []byte, pic CobolPic, precision uint,
+                        mode RoundingMode) (UDec64, error) {
+    if err := pic.check(); err!=nil {
+        return 0, err
+    }
+    if len(b)!=pic.ZonedSize() {
+        return 0, strconv.ErrSyntax
+    }
+    var dbuf [cobolMaxDigits]byte
+    digits := dbuf[:0]
+    last := len(b)-1
+    for i, c := range b {
+        if c&15 > 9 || (i < last && c>>4!=0xf) {
+            return 0, &CobolError{ i, c, ErrCobolDigit }
+        }
+        digits = append(digits, c&15)
+    }
+    neg := false
+    switch b[last]>>4 {
+    case 0xa, 0xc, 0xe, 0xf:
+    case 0xb, 0xd:
+        neg = true
+    default:
+        return 0, &CobolError{ last, b[last], ErrCobolSign }
+    }
+    return cobolDigitsToUDec64(digits, neg, pic, precision, mode)
+}
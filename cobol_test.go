@@ -0,0 +1,200 @@
+/*
+ * cobol_test.go - COBOL packed decimal and zoned decimal tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "errors"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToCobolTC struct {
+    value UDec64
+    precision uint
+    pic CobolPic
+    mode RoundingMode
+    expPacked string
+    expZoned string
+    expError error
+}
+
+func TestUDec64ToCobol(t *testing.T) {
+    testCases := []UDec64ToCobolTC {
+        // PIC S9(7)V99
+        UDec64ToCobolTC{ 1234567, 2, CobolPic{ 7, 2, true }, RoundDown,
+                "001234567c", "f0f0f1f2f3f4f5f6c7", nil },
+        UDec64ToCobolTC{ 0, 2, CobolPic{ 7, 2, true }, RoundDown,
+                "000000000c", "f0f0f0f0f0f0f0f0c0", nil },
+        // PIC 9(3)V99
+        UDec64ToCobolTC{ 12345, 2, CobolPic{ 3, 2, false }, RoundDown,
+                "12345f", "f1f2f3f4f5", nil },
+        UDec64ToCobolTC{ 1234567, 4, CobolPic{ 3, 2, false }, RoundDown,
+                "12345f", "f1f2f3f4f5", nil },
+        UDec64ToCobolTC{ 1234567, 4, CobolPic{ 3, 2, false }, RoundHalfUp,
+                "12346f", "f1f2f3f4f6", nil },
+        UDec64ToCobolTC{ 123, 0, CobolPic{ 3, 2, false }, RoundDown,
+                "12300f", "f1f2f3f0f0", nil },
+        UDec64ToCobolTC{ 123, 0, CobolPic{ 2, 2, false }, RoundDown,
+                "", "", strconv.ErrRange },
+        // PIC S9(4)
+        UDec64ToCobolTC{ 123, 0, CobolPic{ 4, 0, true }, RoundDown,
+                "00123c", "f0f1f2c3", nil },
+        // PIC V9(4)
+        UDec64ToCobolTC{ 5, 3, CobolPic{ 0, 4, false }, RoundDown,
+                "00050f", "f0f0f5f0", nil },
+        // PIC 9(20)
+        UDec64ToCobolTC{ 0xffffffffffffffff, 0, CobolPic{ 20, 0, false }, RoundDown,
+                "018446744073709551615f",
+                "f1f8f4f4f6f7f4f4f0f7f3f7f0f9f5f5f1f6f1f5", nil },
+        UDec64ToCobolTC{ 1, 0, CobolPic{ 0, 0, false }, RoundDown,
+                "", "", ErrCobolPic },
+        UDec64ToCobolTC{ 1, 0, CobolPic{ 30, 9, false }, RoundDown,
+                "", "", ErrCobolPic },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.ToCobolPacked(tc.precision, tc.pic, tc.mode)
+        if tc.expPacked!=hex.EncodeToString(result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: tocobolpacked(%v,%v,%v,%v)->%v,%v!=%x,%v",
+                     i, tc.value, tc.precision, tc.pic, tc.mode,
+                     tc.expPacked, tc.expError, result, err)
+        }
+        if err==nil && len(result)!=tc.pic.PackedSize() {
+            t.Errorf("Size mismatch: %d: %v->%v!=%v", i, tc.pic,
+                     tc.pic.PackedSize(), len(result))
+        }
+        result, err = tc.value.ToCobolZoned(tc.precision, tc.pic, tc.mode)
+        if tc.expZoned!=hex.EncodeToString(result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: tocobolzoned(%v,%v,%v,%v)->%v,%v!=%x,%v",
+                     i, tc.value, tc.precision, tc.pic, tc.mode,
+                     tc.expZoned, tc.expError, result, err)
+        }
+    }
+}
+
+type CobolToUDec64TC struct {
+    value string
+    pic CobolPic
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+    expOffset int // offset in CobolError
+}
+
+func checkCobolError(err, expError error, expOffset int) bool {
+    if !errors.Is(err, expError) { return false }
+    var cerr *CobolError
+    if errors.As(err, &cerr) {
+        return cerr.Offset==expOffset
+    }
+    return true
+}
+
+func TestCobolPackedToUDec64(t *testing.T) {
+    testCases := []CobolToUDec64TC {
+        CobolToUDec64TC{ "001234567c", CobolPic{ 7, 2, true }, 2, RoundDown,
+                1234567, nil, 0 },
+        CobolToUDec64TC{ "001234567f", CobolPic{ 7, 2, true }, 2, RoundDown,
+                1234567, nil, 0 },
+        CobolToUDec64TC{ "001234567c", CobolPic{ 7, 2, true }, 1, RoundDown,
+                123456, nil, 0 },
+        CobolToUDec64TC{ "001234567c", CobolPic{ 7, 2, true }, 1, RoundHalfUp,
+                123457, nil, 0 },
+        CobolToUDec64TC{ "001234567c", CobolPic{ 7, 2, true }, 4, RoundDown,
+                123456700, nil, 0 },
+        CobolToUDec64TC{ "00123c", CobolPic{ 4, 0, true }, 0, RoundDown,
+                123, nil, 0 },
+        CobolToUDec64TC{ "018446744073709551615f", CobolPic{ 20, 0, false }, 0,
+                RoundDown, 0xffffffffffffffff, nil, 0 },
+        CobolToUDec64TC{ "018446744073709551616f", CobolPic{ 20, 0, false }, 0,
+                RoundDown, 0, strconv.ErrRange, 0 },
+        // negative and negative zero
+        CobolToUDec64TC{ "001234567d", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, strconv.ErrRange, 0 },
+        CobolToUDec64TC{ "000000000d", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, nil, 0 },
+        // invalid digit, sign and pad nibble
+        CobolToUDec64TC{ "0012a4567c", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, ErrCobolDigit, 2 },
+        CobolToUDec64TC{ "00123456fc", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, ErrCobolDigit, 4 },
+        CobolToUDec64TC{ "0012345677", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, ErrCobolSign, 4 },
+        CobolToUDec64TC{ "10123c", CobolPic{ 4, 0, true }, 0, RoundDown,
+                0, ErrCobolDigit, 0 },
+        // bad length
+        CobolToUDec64TC{ "1234567c", CobolPic{ 7, 2, true }, 2, RoundDown,
+                0, strconv.ErrSyntax, 0 },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := CobolPackedToUDec64(b, tc.pic, tc.precision, tc.mode)
+        if tc.expected!=result || !checkCobolError(err, tc.expError, tc.expOffset) {
+            t.Errorf("Result mismatch: %d: cobolpackedtoudec64(%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.pic, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestCobolZonedToUDec64(t *testing.T) {
+    testCases := []CobolToUDec64TC {
+        CobolToUDec64TC{ "f1f2f3f4c5", CobolPic{ 3, 2, true }, 2, RoundDown,
+                12345, nil, 0 },
+        CobolToUDec64TC{ "f1f2f3f4f5", CobolPic{ 3, 2, false }, 2, RoundDown,
+                12345, nil, 0 },
+        CobolToUDec64TC{ "f1f2f3f4a5", CobolPic{ 3, 2, true }, 2, RoundDown,
+                12345, nil, 0 },
+        CobolToUDec64TC{ "f1f2f3f4c5", CobolPic{ 3, 2, true }, 1, RoundHalfEven,
+                1234, nil, 0 },
+        CobolToUDec64TC{ "f1f2f3f4c5", CobolPic{ 3, 2, true }, 0, RoundUp,
+                124, nil, 0 },
+        // negative and negative zero
+        CobolToUDec64TC{ "f1f2f3f4d5", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, strconv.ErrRange, 0 },
+        CobolToUDec64TC{ "f0f0f0f0d0", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, nil, 0 },
+        // invalid zone, digit and sign
+        CobolToUDec64TC{ "f1f2c3f4c5", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, ErrCobolDigit, 2 },
+        CobolToUDec64TC{ "f1fbf3f4c5", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, ErrCobolDigit, 1 },
+        CobolToUDec64TC{ "f1f2f3f485", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, ErrCobolSign, 4 },
+        CobolToUDec64TC{ "f1f2f3f4", CobolPic{ 3, 2, true }, 2, RoundDown,
+                0, strconv.ErrSyntax, 0 },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := CobolZonedToUDec64(b, tc.pic, tc.precision, tc.mode)
+        if tc.expected!=result || !checkCobolError(err, tc.expError, tc.expOffset) {
+            t.Errorf("Result mismatch: %d: cobolzonedtoudec64(%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.pic, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+    err := error(&CobolError{ 3, 0x8a, ErrCobolSign })
+    if err.Error()!="godec64: invalid COBOL sign at offset 3 (byte 0x8a)" {
+        t.Errorf("Error message mismatch: %v", err)
+    }
+}
@@ -0,0 +1,231 @@
+/*
+ * columnar.go - Avro, Parquet and Arrow decimal logical types
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "encoding/binary"
+    "errors"
+    "strconv"
+)
+
+// invalid DECIMAL(p,s) logical type
+var ErrDecimalType = errors.New("godec64: invalid DECIMAL(p,s) type")
+
+const (
+    decimalInt32MaxPrecision = 9
+    decimalInt64MaxPrecision = 18
+    decimal128MaxPrecision = 38
+)
+
+func checkDecimalType(p, s, maxP uint) error {
+    if p==0 || p > maxP || s > p {
+        return ErrDecimalType
+    }
+    return nil
+}
+
+// returns true if v has no more than p digits
+func fitsInDigits(v uint64, p uint) bool {
+    if p < uint(len(uint64_powers)) {
+        return v < uint64_powers[p]
+    }
+    return p > 19 || v < 10000000000000000000
+}
+
+// get unscaled value of DECIMAL(p,s) from value with precision
+func (a UDec64) toUnscaled(precision, p, s uint,
+                           mode RoundingMode) (uint64, error) {
+    v := uint64(a)
+    if s < precision {
+        v = divPow10(v, precision-s, mode)
+    } else {
+        var ok bool
+        v, ok = mulPow10(v, s-precision)
+        if !ok { return 0, strconv.ErrRange }
+    }
+    if !fitsInDigits(v, p) {
+        return 0, strconv.ErrRange
+    }
+    return v, nil
+}
+
+// convert unscaled value of DECIMAL(p,s) to UDec64 with precision
+func fromUnscaled(v uint64, p, s, precision uint,
+                  mode RoundingMode) (UDec64, error) {
+    if !fitsInDigits(v, p) {
+        return 0, strconv.ErrRange
+    }
+    return coefToUDec64(v, -int(s), precision, mode)
+}
+
+// convert to unscaled INT32 of DECIMAL(p,s) (Parquet INT32, p<=9)
+func (a UDec64) ToDecimalInt32(precision, p, s uint,
+                               mode RoundingMode) (int32, error) {
+    if err := checkDecimalType(p, s, decimalInt32MaxPrecision); err!=nil {
+        return 0, err
+    }
+    v, err := a.toUnscaled(precision, p, s, mode)
+    return int32(v), err
+}
+
+// convert unscaled INT32 of DECIMAL(p,s) to UDec64 with precision
+func DecimalInt32ToUDec64(v int32, p, s, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if err := checkDecimalType(p, s, decimalInt32MaxPrecision); err!=nil {
+        return 0, err
+    }
+    if v < 0 { return 0, strconv.ErrRange }
+    return fromUnscaled(uint64(v), p, s, precision, mode)
+}
+
+// convert to unscaled INT64 of DECIMAL(p,s) (Parquet INT64, p<=18)
+func (a UDec64) ToDecimalInt64(precision, p, s uint,
+                               mode RoundingMode) (int64, error) {
+    if err := checkDecimalType(p, s, decimalInt64MaxPrecision); err!=nil {
+        return 0, err
+    }
+    v, err := a.toUnscaled(precision, p, s, mode)
+    return int64(v), err
+}
+
+// convert unscaled INT64 of DECIMAL(p,s) to UDec64 with precision
+func DecimalInt64ToUDec64(v int64, p, s, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if err := checkDecimalType(p, s, decimalInt64MaxPrecision); err!=nil {
+        return 0, err
+    }
+    if v < 0 { return 0, strconv.ErrRange }
+    return fromUnscaled(uint64(v), p, s, precision, mode)
+}
+
+// append unscaled value of DECIMAL(p,s) as shortest big-endian two's
+// complement bytes (Avro bytes, Parquet BYTE_ARRAY) to dst
+func (a UDec64) AppendDecimalBytes(dst []byte, precision, p, s uint,
+                                   mode RoundingMode) ([]byte, error) {
+    if err := checkDecimalType(p, s, ^uint(0)); err!=nil {
+        return dst, err
+    }
+    v, err := a.toUnscaled(precision, p, s, mode)
+    if err!=nil { return dst, err }
+    n := 1
+    for ; n < 9 && v >= uint64(1)<<(8*uint(n)-1); n++ { }
+    for i:=n-1; i >= 0; i-- {
+        if i >= 8 {
+            dst = append(dst, 0)
+        } else {
+            dst = append(dst, byte(v>>(8*uint(i))))
+        }
+    }
+    return dst, nil
+}
+
+// convert to unscaled value of DECIMAL(p,s) as shortest big-endian two's
+// complement bytes (Avro bytes, Parquet BYTE_ARRAY)
+func (a UDec64) ToDecimalBytes(precision, p, s uint,
+                               mode RoundingMode) ([]byte, error) {
+    return a.AppendDecimalBytes(nil, precision, p, s, mode)
+}
+
+// append unscaled value of DECIMAL(p,s) as big-endian two's complement
+// bytes of given size (Avro fixed, Parquet FIXED_LEN_BYTE_ARRAY) to dst
+func (a UDec64) AppendDecimalFixed(dst []byte, precision, p, s uint, size int,
+                                   mode RoundingMode) ([]byte, error) {
+    if err := checkDecimalType(p, s, ^uint(0)); err!=nil {
+        return dst, err
+    }
+    v, err := a.toUnscaled(precision, p, s, mode)
+    if err!=nil { return dst, err }
+    if size < 9 && (size <= 0 || v >= uint64(1)<<(8*uint(size)-1)) {
+        return dst, strconv.ErrRange
+    }
+    for i:=size-1; i >= 0; i-- {
+        if i >= 8 {
+            dst = append(dst, 0)
+        } else {
+            dst = append(dst, byte(v>>(8*uint(i))))
+        }
+    }
+    return dst, nil
+}
+
+// convert to unscaled value of DECIMAL(p,s) as big-endian two's complement
+// bytes of given size (Avro fixed, Parquet FIXED_LEN_BYTE_ARRAY)
+func (a UDec64) ToDecimalFixed(precision, p, s uint, size int,
+                               mode RoundingMode) ([]byte, error) {
+    return a.AppendDecimalFixed(nil, precision, p, s, size, mode)
+}
+
+// convert unscaled value of DECIMAL(p,s) in big-endian two's complement bytes
+// (Avro bytes and fixed, Parquet BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY)
+// to UDec64 with precision and rounding mode
+func DecimalBytesToUDec64(b []byte, p, s, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if err := checkDecimalType(p, s, ^uint(0)); err!=nil {
+        return 0, err
+    }
+    if len(b)==0 {
+        return 0, strconv.ErrSyntax
+    }
+    if b[0]&0x80!=0 {
+        return 0, strconv.ErrRange // negative
+    }
+    for ; len(b) > 0 && b[0]==0; b = b[1:] { }
+    if len(b) > 8 {
+        return 0, strconv.ErrRange
+    }
+    var v uint64
+    for _, c := range b {
+        v = v<<8 | uint64(c)
+    }
+    return fromUnscaled(v, p, s, precision, mode)
+}
+
+// convert to unscaled value of DECIMAL(p,s) as Arrow Decimal128
+// (little-endian two's complement, p<=38)
+func (a UDec64) ToArrowDecimal128(precision, p, s uint,
+                                  mode RoundingMode) ([16]byte, error) {
+    var out [16]byte
+    if err := checkDecimalType(p, s, decimal128MaxPrecision); err!=nil {
+        return out, err
+    }
+    v, err := a.toUnscaled(precision, p, s, mode)
+    if err!=nil { return out, err }
+    binary.LittleEndian.PutUint64(out[:8], v)
+    return out, nil
+}
+
+// convert unscaled value of DECIMAL(p,s) as Arrow Decimal128 (little-endian
+// two's complement, 16 bytes) to UDec64 with precision and rounding mode
+func ArrowDecimal128ToUDec64(b []byte, p, s, precision uint,
+                             mode RoundingMode) (UDec64, error) {
+    if err := checkDecimalType(p, s, decimal128MaxPrecision); err!=nil {
+        return 0, err
+    }
+    if len(b)!=16 {
+        return 0, strconv.ErrSyntax
+    }
+    if binary.LittleEndian.Uint64(b[8:])!=0 {
+        return 0, strconv.ErrRange // negative or too big
+    }
+    return fromUnscaled(binary.LittleEndian.Uint64(b[:8]), p, s, precision, mode)
+}
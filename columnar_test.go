@@ -0,0 +1,262 @@
+/*
+ * columnar_test.go - Avro, Parquet and Arrow decimal logical types tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToDecimalIntTC struct {
+    value UDec64
+    precision uint
+    p, s uint
+    mode RoundingMode
+    expected int64
+    expError error
+}
+
+func TestUDec64ToDecimalInt(t *testing.T) {
+    testCases := []UDec64ToDecimalIntTC {
+        UDec64ToDecimalIntTC{ 0, 2, 9, 2, RoundDown, 0, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 9, 2, RoundDown, 12345, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 9, 4, RoundDown, 1234500, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 5, 1, RoundDown, 1234, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 5, 1, RoundHalfUp, 1235, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 4, 2, RoundDown, 0, strconv.ErrRange },
+        UDec64ToDecimalIntTC{ 12345, 2, 9, 9, RoundDown, 0, strconv.ErrRange },
+        UDec64ToDecimalIntTC{ 999999999, 0, 9, 0, RoundDown, 999999999, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 10, 2, RoundDown, 0, ErrDecimalType },
+        UDec64ToDecimalIntTC{ 12345, 2, 0, 0, RoundDown, 0, ErrDecimalType },
+        UDec64ToDecimalIntTC{ 12345, 2, 4, 5, RoundDown, 0, ErrDecimalType },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.ToDecimalInt32(tc.precision, tc.p, tc.s, tc.mode)
+        if tc.expected!=int64(result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: todecimalint32(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.p, tc.s, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+        if err!=nil { continue }
+        back, err := DecimalInt32ToUDec64(result, tc.p, tc.s, tc.s, RoundDown)
+        if back!=tc.value.Convert(tc.precision, tc.s, tc.mode==RoundHalfUp) ||
+            err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v->%v,%v", i, result, back, err)
+        }
+    }
+    testCases = []UDec64ToDecimalIntTC {
+        UDec64ToDecimalIntTC{ 12345, 2, 18, 2, RoundDown, 12345, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 18, 15, RoundDown, 123450000000000000, nil },
+        UDec64ToDecimalIntTC{ 12345, 2, 18, 16, RoundDown, 0, strconv.ErrRange },
+        UDec64ToDecimalIntTC{ 0xffffffffffffffff, 2, 18, 0, RoundDown,
+                184467440737095516, nil },
+        UDec64ToDecimalIntTC{ 0xffffffffffffffff, 2, 18, 1, RoundDown,
+                0, strconv.ErrRange },
+        UDec64ToDecimalIntTC{ 12345, 2, 19, 2, RoundDown, 0, ErrDecimalType },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.ToDecimalInt64(tc.precision, tc.p, tc.s, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: todecimalint64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.p, tc.s, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+type DecimalIntToUDec64TC struct {
+    value int64
+    p, s uint
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestDecimalIntToUDec64(t *testing.T) {
+    testCases := []DecimalIntToUDec64TC {
+        DecimalIntToUDec64TC{ 12345, 9, 2, 2, RoundDown, 12345, nil },
+        DecimalIntToUDec64TC{ 12345, 9, 2, 4, RoundDown, 1234500, nil },
+        DecimalIntToUDec64TC{ 12345, 9, 2, 1, RoundDown, 1234, nil },
+        DecimalIntToUDec64TC{ 12345, 9, 2, 1, RoundHalfEven, 1234, nil },
+        DecimalIntToUDec64TC{ 12345, 9, 2, 1, RoundHalfUp, 1235, nil },
+        DecimalIntToUDec64TC{ 12345, 4, 2, 2, RoundDown, 0, strconv.ErrRange },
+        DecimalIntToUDec64TC{ -12345, 9, 2, 2, RoundDown, 0, strconv.ErrRange },
+        DecimalIntToUDec64TC{ 12345, 9, 10, 2, RoundDown, 0, ErrDecimalType },
+    }
+    for i, tc := range testCases {
+        result, err := DecimalInt32ToUDec64(int32(tc.value), tc.p, tc.s,
+                                            tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: decimalint32toudec64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.p, tc.s, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+    testCases = []DecimalIntToUDec64TC {
+        DecimalIntToUDec64TC{ 12345, 18, 2, 2, RoundDown, 12345, nil },
+        DecimalIntToUDec64TC{ 999999999999999999, 18, 0, 1, RoundDown,
+                9999999999999999990, nil },
+        DecimalIntToUDec64TC{ 999999999999999999, 18, 0, 2, RoundDown,
+                0, strconv.ErrRange },
+        DecimalIntToUDec64TC{ 1000000000000000000, 18, 0, 0, RoundDown,
+                0, strconv.ErrRange },
+        DecimalIntToUDec64TC{ -1, 18, 0, 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        result, err := DecimalInt64ToUDec64(tc.value, tc.p, tc.s,
+                                            tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: decimalint64toudec64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.p, tc.s, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+type UDec64ToDecimalBytesTC struct {
+    value UDec64
+    precision uint
+    p, s uint
+    size int // 0 - shortest form
+    expected string
+    expError error
+}
+
+func TestUDec64ToDecimalBytes(t *testing.T) {
+    testCases := []UDec64ToDecimalBytesTC {
+        UDec64ToDecimalBytesTC{ 0, 2, 9, 2, 0, "00", nil },
+        UDec64ToDecimalBytesTC{ 127, 0, 9, 0, 0, "7f", nil },
+        UDec64ToDecimalBytesTC{ 128, 0, 9, 0, 0, "0080", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 9, 2, 0, "3039", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 9, 3, 0, "01e23a", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 4, 2, 0, "", strconv.ErrRange },
+        UDec64ToDecimalBytesTC{ 0xffffffffffffffff, 0, 20, 0, 0,
+                "00ffffffffffffffff", nil },
+        UDec64ToDecimalBytesTC{ 0xffffffffffffffff, 0, 38, 2, 0,
+                "", strconv.ErrRange },
+        UDec64ToDecimalBytesTC{ 0xffffffffffffffff, 0, 19, 0, 0,
+                "", strconv.ErrRange },
+        UDec64ToDecimalBytesTC{ 12345, 2, 9, 2, 4, "00003039", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 9, 2, 2, "3039", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 9, 3, 2, "", strconv.ErrRange },
+        UDec64ToDecimalBytesTC{ 12345, 2, 30, 2, 16,
+                "00000000000000000000000000003039", nil },
+        UDec64ToDecimalBytesTC{ 0x8000000000000000, 0, 20, 0, 8,
+                "", strconv.ErrRange },
+        UDec64ToDecimalBytesTC{ 0x8000000000000000, 0, 20, 0, 9,
+                "008000000000000000", nil },
+        UDec64ToDecimalBytesTC{ 12345, 2, 0, 0, 0, "", ErrDecimalType },
+    }
+    for i, tc := range testCases {
+        var result []byte
+        var err error
+        if tc.size==0 {
+            result, err = tc.value.ToDecimalBytes(tc.precision, tc.p, tc.s,
+                                                  RoundDown)
+        } else {
+            result, err = tc.value.ToDecimalFixed(tc.precision, tc.p, tc.s,
+                                                  tc.size, RoundDown)
+        }
+        if tc.expected!=hex.EncodeToString(result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: todecimalbytes(%v,%v,%v,%v,%v)->%v,%v!=%x,%v",
+                     i, tc.value, tc.precision, tc.p, tc.s, tc.size,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+type DecimalBytesToUDec64TC struct {
+    value string
+    p, s uint
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestDecimalBytesToUDec64(t *testing.T) {
+    testCases := []DecimalBytesToUDec64TC {
+        DecimalBytesToUDec64TC{ "00", 9, 2, 2, RoundDown, 0, nil },
+        DecimalBytesToUDec64TC{ "3039", 9, 2, 2, RoundDown, 12345, nil },
+        DecimalBytesToUDec64TC{ "0000003039", 9, 2, 2, RoundDown, 12345, nil },
+        DecimalBytesToUDec64TC{ "3039", 9, 2, 3, RoundDown, 123450, nil },
+        DecimalBytesToUDec64TC{ "3039", 9, 2, 1, RoundDown, 1234, nil },
+        DecimalBytesToUDec64TC{ "3039", 9, 2, 1, RoundHalfUp, 1235, nil },
+        DecimalBytesToUDec64TC{ "00000000000000000000000000003039", 30, 2, 2,
+                RoundDown, 12345, nil },
+        DecimalBytesToUDec64TC{ "00ffffffffffffffff", 20, 0, 0, RoundDown,
+                0xffffffffffffffff, nil },
+        DecimalBytesToUDec64TC{ "01ffffffffffffffff", 38, 0, 0, RoundDown,
+                0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "0186a0", 5, 0, 0, RoundDown, 0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "ff", 9, 2, 2, RoundDown, 0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "", 9, 2, 2, RoundDown, 0, strconv.ErrSyntax },
+        DecimalBytesToUDec64TC{ "3039", 9, 10, 2, RoundDown, 0, ErrDecimalType },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := DecimalBytesToUDec64(b, tc.p, tc.s, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: decimalbytestoudec64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.p, tc.s, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestArrowDecimal128(t *testing.T) {
+    result, err := UDec64(12345).ToArrowDecimal128(2, 38, 4, RoundDown)
+    if hex.EncodeToString(result[:])!="44d61200000000000000000000000000" ||
+        err!=nil {
+        t.Errorf("Result mismatch: toarrowdecimal128->%x,%v", result, err)
+    }
+    _, err = UDec64(12345).ToArrowDecimal128(2, 39, 4, RoundDown)
+    if err!=ErrDecimalType {
+        t.Errorf("Result mismatch: toarrowdecimal128->%v", err)
+    }
+    testCases := []DecimalBytesToUDec64TC {
+        DecimalBytesToUDec64TC{ "44d61200000000000000000000000000", 38, 4, 2,
+                RoundDown, 12345, nil },
+        DecimalBytesToUDec64TC{ "44d61200000000000000000000000000", 38, 4, 1,
+                RoundUp, 1235, nil },
+        DecimalBytesToUDec64TC{ "ffffffffffffffff0000000000000000", 20, 0, 0,
+                RoundDown, 0xffffffffffffffff, nil },
+        DecimalBytesToUDec64TC{ "ffffffffffffffff0000000000000000", 19, 0, 0,
+                RoundDown, 0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "00000000000000000100000000000000", 38, 0, 0,
+                RoundDown, 0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "ffffffffffffffffffffffffffffffff", 38, 0, 0,
+                RoundDown, 0, strconv.ErrRange },
+        DecimalBytesToUDec64TC{ "44d612", 38, 4, 2, RoundDown, 0, strconv.ErrSyntax },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := ArrowDecimal128ToUDec64(b, tc.p, tc.s, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: arrowdecimal128toudec64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.p, tc.s, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
@@ -0,0 +1,147 @@
+/*
+ * currency.go - currency formatting
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "sync"
+)
+
+// unknown currency code
+var ErrCurrency = errors.New("godec64: unknown currency")
+
+// currency display mode
+type CurrencyDisplay uint8
+
+const (
+    // display currency symbol of locale (for example "$" or "zł")
+    CurrencySymbol CurrencyDisplay = iota
+    // display ISO 4217 code (for example "USD")
+    CurrencyCode
+)
+
+// currency formatting options. Zero value gives currency symbol and
+// rounding half to even
+type CurrencyOptions struct {
+    // currency display mode
+    Display CurrencyDisplay
+    // format value as negative (UDec64 holds only magnitude)
+    Negative bool
+    // rounding mode used if value has more digits than minor unit of currency.
+    // Used only if HasRounding is set, otherwise rounding half to even is used
+    Rounding RoundingMode
+    HasRounding bool
+    // CLDR numbering system (for example "latn") instead of locale's
+    NumberingSystem string
+}
+
+// get ISO 4217 currency by alphabetic code (uppercase)
+func LookupCurrency(code string) (Currency, bool) {
+    c, ok := iso4217Currencies[code]
+    return c, ok
+}
+
+// compiled currency patterns by pattern
+var currencyPatterns sync.Map
+
+// get compiled currency pattern and currency symbol for language tag. Returns
+// also false if locale formatting info is found (for example registered
+// locale) before CLDR currency pattern in parent chain, then grouping should
+// be taken from locale formatting info
+func findCurrencyFormat(lang string, c *Currency) (*NumberPattern, string,
+                                                   bool, error) {
+    tag, _ := ParseLanguageTag(lang)
+    pattern, symbol := "", ""
+    ownPattern, locFmtFound := false, false
+    registeredLocalesMutex.RLock()
+    for locale := tag.localeID(); ; locale = localeParent(locale) {
+        if symbol=="" {
+            symbol = localeCurrencySymbols[locale+":"+c.Code]
+        }
+        if pattern=="" {
+            pattern = localeCurrencyPatterns[locale]
+            ownPattern = pattern!="" && !locFmtFound
+        }
+        if _, ok := lookupLocFmt(locale); ok {
+            locFmtFound = true
+        }
+        if locale=="root" { break }
+    }
+    registeredLocalesMutex.RUnlock()
+    if symbol=="" {
+        symbol = c.Symbol
+    }
+    if p, ok := currencyPatterns.Load(pattern); ok {
+        return p.(*NumberPattern), symbol, ownPattern, nil
+    }
+    p, err := CompilePattern(pattern)
+    if err!=nil { return nil, "", false, err }
+    currencyPatterns.Store(pattern, p)
+    return p, symbol, ownPattern, nil
+}
+
+// append number formatted as amount of currency (ISO 4217 code) to dst.
+// Number is rounded to minor unit of currency (for example 0 digits for JPY,
+// 3 for BHD). Currency symbol (or code) placement, spacing and negative style
+// are taken from CLDR currency pattern of locale. Grouping is taken from
+// locale formatting info if locale has no own CLDR currency pattern
+func (a UDec64) AppendCurrency(dst []byte, precision uint, currency, lang string,
+                               opts CurrencyOptions) ([]byte, error) {
+    c, ok := iso4217Currencies[currency]
+    if !ok { return dst, ErrCurrency }
+    cp, symbol, ownPattern, err := findCurrencyFormat(lang, &c)
+    if err!=nil { return dst, err }
+    l := GetLocFmtNumbering(lang, opts.NumberingSystem)
+    p := *cp
+    if !ownPattern && p.primaryGroup!=0 {
+        p.primaryGroup, p.secondaryGroup = 3, 3
+        if l.Sep100and1000 {
+            p.secondaryGroup = 2
+        }
+    }
+    if opts.Display==CurrencyCode {
+        symbol = c.Code
+    }
+    p.Currency = symbol
+    p.minFrac, p.maxFrac = c.MinorUnits, c.MinorUnits
+    if opts.HasRounding {
+        p.Rounding = opts.Rounding
+    } else {
+        p.Rounding = RoundHalfEven
+    }
+    return p.AppendFormat(dst, a, precision, opts.Negative, l), nil
+}
+
+// format number as amount of currency (ISO 4217 code) with options
+func (a UDec64) FormatCurrencyOpts(precision uint, currency, lang string,
+                                   opts CurrencyOptions) (string, error) {
+    dst, err := a.AppendCurrency(nil, precision, currency, lang, opts)
+    return string(dst), err
+}
+
+// format number as amount of currency (ISO 4217 code), for example
+// UDec64(123456).FormatCurrency(2, "EUR", "de") returns "1.234,56 €"
+// (with no-break space before symbol)
+func (a UDec64) FormatCurrency(precision uint, currency, lang string) (string, error) {
+    return a.FormatCurrencyOpts(precision, currency, lang, CurrencyOptions{})
+}
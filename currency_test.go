@@ -0,0 +1,159 @@
+/*
+ * currency_test.go - currency formatting tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "testing"
+)
+
+type CurrencyFormatTC struct {
+    value UDec64
+    precision uint
+    currency string
+    lang string
+    opts CurrencyOptions
+    expected string
+    expError error
+}
+
+func TestUDec64FormatCurrency(t *testing.T) {
+    testCases := []CurrencyFormatTC {
+        CurrencyFormatTC{ 123456, 2, "USD", "en", CurrencyOptions{}, "$1,234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "USD", "en-US", CurrencyOptions{ Negative: true },
+                "-$1,234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "EUR", "de", CurrencyOptions{}, "1.234,56\u00a0€", nil },
+        CurrencyFormatTC{ 123456, 2, "EUR", "fr", CurrencyOptions{},
+                "1\u202f234,56\u00a0€", nil },
+        CurrencyFormatTC{ 123456, 2, "EUR", "nl", CurrencyOptions{ Negative: true },
+                "€\u00a0-1.234,56", nil },
+        CurrencyFormatTC{ 123456, 2, "EUR", "en", CurrencyOptions{}, "€1,234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "PLN", "pl", CurrencyOptions{},
+                "1\u00a0234,56\u00a0zł", nil },
+        CurrencyFormatTC{ 123456, 2, "PLN", "pl", CurrencyOptions{ Display: CurrencyCode },
+                "1\u00a0234,56\u00a0PLN", nil },
+        CurrencyFormatTC{ 123456, 2, "PLN", "en", CurrencyOptions{}, "PLN\u00a01,234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "CHF", "de-CH", CurrencyOptions{},
+                "CHF\u00a01’234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "CHF", "de-CH", CurrencyOptions{ Negative: true },
+                "CHF-1’234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "USD", "en", CurrencyOptions{ Display: CurrencyCode },
+                "USD\u00a01,234.56", nil },
+        // minor units
+        CurrencyFormatTC{ 123456, 2, "JPY", "ja", CurrencyOptions{}, "￥1,235", nil },
+        CurrencyFormatTC{ 123450, 2, "JPY", "en", CurrencyOptions{}, "¥1,234", nil },
+        CurrencyFormatTC{ 123450, 2, "JPY", "en", CurrencyOptions{ HasRounding: true,
+                Rounding: RoundHalfUp }, "¥1,235", nil },
+        CurrencyFormatTC{ 1234567, 3, "BHD", "en", CurrencyOptions{},
+                "BHD\u00a01,234.567", nil },
+        CurrencyFormatTC{ 1234, 0, "BHD", "en", CurrencyOptions{},
+                "BHD\u00a01,234.000", nil },
+        CurrencyFormatTC{ 123456789, 5, "CLF", "es-CL", CurrencyOptions{},
+                "CLF\u00a01.234,5679", nil },
+        CurrencyFormatTC{ 1234, 0, "CLP", "es-CL", CurrencyOptions{ Negative: true },
+                "$-1.234", nil },
+        CurrencyFormatTC{ 5, 1, "USD", "en", CurrencyOptions{}, "$0.50", nil },
+        CurrencyFormatTC{ 0, 2, "USD", "en", CurrencyOptions{ Negative: true },
+                "$0.00", nil },
+        // locale patterns and symbols
+        CurrencyFormatTC{ 12345678, 2, "INR", "hi", CurrencyOptions{}, "₹1,23,456.78", nil },
+        CurrencyFormatTC{ 12345678, 2, "INR", "en-IN", CurrencyOptions{},
+                "₹1,23,456.78", nil },
+        CurrencyFormatTC{ 123456, 2, "BRL", "pt-BR", CurrencyOptions{},
+                "R$\u00a01.234,56", nil },
+        CurrencyFormatTC{ 123456, 2, "MXN", "es-MX", CurrencyOptions{}, "$1,234.56", nil },
+        CurrencyFormatTC{ 123456, 2, "MXN", "es", CurrencyOptions{},
+                "1.234,56\u00a0MX$", nil },
+        CurrencyFormatTC{ 123456, 2, "SEK", "sv", CurrencyOptions{},
+                "1\u00a0234,56\u00a0kr", nil },
+        CurrencyFormatTC{ 123456, 2, "DKK", "da", CurrencyOptions{}, "1.234,56\u00a0kr.", nil },
+        CurrencyFormatTC{ 123456, 2, "ZAR", "en-ZA", CurrencyOptions{},
+                "R\u00a01\u00a0234,56", nil },
+        CurrencyFormatTC{ 123456, 2, "EGP", "ar", CurrencyOptions{},
+                "١٬٢٣٤٫٥٦\u00a0EGP", nil },
+        CurrencyFormatTC{ 123456, 2, "EGP", "ar", CurrencyOptions{ NumberingSystem: "latn" },
+                "1,234.56\u00a0EGP", nil },
+        CurrencyFormatTC{ 123456, 2, "EUR", "xx", CurrencyOptions{}, "€\u00a01,234.56", nil },
+        // errors
+        CurrencyFormatTC{ 123456, 2, "XXX", "en", CurrencyOptions{}, "", ErrCurrency },
+        CurrencyFormatTC{ 123456, 2, "usd", "en", CurrencyOptions{}, "", ErrCurrency },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.FormatCurrencyOpts(tc.precision, tc.currency, tc.lang,
+                                                   tc.opts)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: fmt(%v,%v,%s,%s,%v)->%q,%v!=%q,%v",
+                     i, tc.value, tc.precision, tc.currency, tc.lang, tc.opts,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+    if result, err := UDec64(123456).FormatCurrency(2, "EUR", "de");
+            result!="1.234,56\u00a0€" || err!=nil {
+        t.Errorf("Result mismatch: fmt->%q,%v!=%q,%v", "1.234,56\u00a0€", nil,
+                 result, err)
+    }
+}
+
+func TestLookupCurrency(t *testing.T) {
+    testCases := []Currency {
+        Currency{ "USD", 840, 2, "$" },
+        Currency{ "JPY", 392, 0, "¥" },
+        Currency{ "BHD", 48, 3, "BHD" },
+        Currency{ "CLF", 990, 4, "CLF" },
+        Currency{ "KWD", 414, 3, "KWD" },
+        Currency{ "XOF", 952, 0, "F CFA" },
+        // narrow symbols are not used as default symbols
+        Currency{ "AMD", 51, 2, "AMD" },
+        Currency{ "NGN", 566, 2, "NGN" },
+    }
+    for i, tc := range testCases {
+        result, ok := LookupCurrency(tc.Code)
+        if tc!=result || !ok {
+            t.Errorf("Result mismatch: %d: lookup(%v)->%v!=%v,%v", i, tc.Code,
+                     tc, result, ok)
+        }
+    }
+    if _, ok := LookupCurrency("XAU"); ok {
+        t.Errorf("Result mismatch: lookup(XAU)")
+    }
+}
+
+func TestFormatCurrencyRegisteredLocale(t *testing.T) {
+    // registered locale without CLDR currency pattern uses its own grouping
+    if err := RegisterLocale("qaa", LocFmt{ '.', ',', ',', true, latnDigits });
+            err!=nil {
+        t.Fatalf("register: %v", err)
+    }
+    defer UnregisterLocale("qaa")
+    for _, lang := range []string{ "qaa", "qaa-IN" } {
+        result, err := UDec64(123456789).FormatCurrency(2, "USD", lang)
+        if result!="$\u00a012,34,567.89" || err!=nil {
+            t.Errorf("Result mismatch: fmtcurrency(%v)->%v!=%v,%v", lang,
+                     "$\u00a012,34,567.89", result, err)
+        }
+    }
+    // built-in locale with own CLDR currency pattern
+    result, err := UDec64(123456789).FormatCurrency(2, "USD", "en")
+    if result!="$1,234,567.89" || err!=nil {
+        t.Errorf("Result mismatch: fmtcurrency(en)->%v!=%v,%v", "$1,234,567.89",
+                 result, err)
+    }
+}
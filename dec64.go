@@ -54,6 +54,68 @@ var uint64_powers []uint64 = []uint64{
     1000000000000000000,
 }
 
+// rounding mode
+type RoundingMode uint8
+
+const (
+    // truncate (round toward zero)
+    RoundDown RoundingMode = iota
+    // round away from zero
+    RoundUp
+    // round to nearest, ties away from zero
+    RoundHalfUp
+    // round to nearest, ties toward zero
+    RoundHalfDown
+    // round to nearest, ties to even
+    RoundHalfEven
+)
+
+// returns true if quotient q should be incremented. half is result of comparison
+// remainder with half of divisor (-1, 0, 1), exact is true if remainder is zero
+func roundUp(q uint64, half int, exact bool, mode RoundingMode) bool {
+    switch mode {
+    case RoundUp:
+        return !exact
+    case RoundHalfUp:
+        return half>=0 && !exact
+    case RoundHalfDown:
+        return half>0
+    case RoundHalfEven:
+        return half>0 || (half==0 && !exact && (q&1)!=0)
+    }
+    return false
+}
+
+// compare remainder r with half of divisor d
+func cmpHalf(r, d uint64) int {
+    if r < d-r {
+        return -1
+    } else if r > d-r {
+        return 1
+    }
+    return 0
+}
+
+// divide a by 10^k with rounding
+func divPow10(a uint64, k uint, mode RoundingMode) uint64 {
+    if k==0 { return a }
+    if k >= uint(len(uint64_powers)) {
+        // a is always lower than half of 10^k (if k>19)
+        if k==19 {
+            d := uint64(10000000000000000000)
+            q, r := a/d, a%d
+            if roundUp(q, cmpHalf(r, d), r==0, mode) { q++ }
+            return q
+        }
+        if roundUp(0, -1, a==0, mode) { return 1 }
+        return 0
+    }
+    d := uint64_powers[k]
+    q, r := a/d, a%d
+    if roundUp(q, cmpHalf(r, d), r==0, mode) { q++ }
+    return q
+}
+
 func (a UDec64) Mul(b UDec64, precision uint, rounding bool) UDec64 {
     chi, clo := bits.Mul64(uint64(a), uint64(b))
     quo, rem := bits.Div64(chi, clo, uint64_powers[precision])
@@ -80,57 +142,19 @@ func DivFull(hi, lo, b UDec64) (UDec64, UDec64) {
     return UDec64(quo), UDec64(rem)
 }
 
-var zeroPart []byte = []byte("0.000000000000000000000000000")
-
+// new format routine with additional displayPrecision argument
 func (a UDec64) FormatNew(precision, displayPrecision uint, trimZeroes bool) string {
-    if a==0 { return "0.0" }
-    if precision==0 { return strconv.FormatUint(uint64(a), 10) }
-    str := strconv.AppendUint(nil, uint64(a), 10)
-    slen := len(str)
-    i := slen
-    if slen <= int(precision) {
-        if trimZeroes {
-            for i--; i>=0; i-- {
-                if str[i]!='0' { break }
-            }
-            i++
-        }
-        var os strings.Builder
-        os.Write(zeroPart[:2+int(precision)-slen])
-        os.Write(str[:i])
-        return os.String()
-    }
-    if trimZeroes {
-        for i--; i>=slen-int(precision); i-- {
-            if str[i]!='0' { break }
-        }
-        i++
-    }
-    var os strings.Builder
-    os.Grow(i)
-    os.Write(str[:slen-int(precision)])
-    dotPos := os.Len()
-    os.WriteByte('.')
-    if (trimZeroes && precision<displayPrecision) || precision==displayPrecision {
-        os.Write(str[slen-int(precision):i])
-    } else if precision>displayPrecision {
-        x := slen-int(precision)+int(displayPrecision) // fix
-        if trimZeroes {
-            if x>i { x = i }
-            for x--; str[x]=='0'; x-- { }
-            x++
-        }
-        os.Write(str[slen-int(precision):x])
-    } else {
-        os.Write(str[slen-int(precision):i])
-        for i:=0; i < int(displayPrecision-precision); i++ {
-            os.WriteByte('0')
-        }
-    }
-    if os.Len()==dotPos+1 {
-        os.WriteByte('0')
-    }
-    return os.String()
+    return a.FormatOpts(precision, legacyFormatOptions(precision,
+                displayPrecision, trimZeroes))
+}
+
+// new format routine with rounding mode. If displayPrecision is lower than precision
+// then value will be rounded to displayPrecision digits after comma
+func (a UDec64) FormatNewR(precision, displayPrecision uint, trimZeroes bool,
+                           mode RoundingMode) string {
+    opts := legacyFormatOptions(precision, displayPrecision, trimZeroes)
+    opts.Rounding = mode
+    return a.FormatOpts(precision, opts)
 }
 
 // format number
@@ -141,56 +165,16 @@ func (a UDec64) Format(precision uint, trimZeroes bool) string {
 // new format routine with additional displayPrecision argument. Format to bytes
 func (a UDec64) FormatNewBytes(precision, displayPrecision uint,
                                 trimZeroes bool) []byte {
-    if a==0 { return zeroPart[:3] }
-    if precision==0 { return strconv.AppendUint(nil, uint64(a), 10) }
-    str := strconv.AppendUint(nil, uint64(a), 10)
-    slen := len(str)
-    i := slen
-    if slen <= int(precision) {
-        if trimZeroes {
-            for i--; i>=0; i-- {
-                if str[i]!='0' { break }
-            }
-            i++
-        }
-        l := 2+int(precision)-slen
-        os := make([]byte, l+i)
-        copy(os[:l], zeroPart[:l])
-        copy(os[l:], str[:i])
-        return os
-    }
-    if trimZeroes {
-        for i--; i>=slen-int(precision); i-- {
-            if str[i]!='0' { break }
-        }
-        i++
-    }
-    os := make([]byte, i+1)
-    l := slen-int(precision)
-    copy(os[:l], str[:l])
-    dotPos := l
-    os[l] = '.'
-    copy(os[l+1:], str[slen-int(precision):i])
-    if !(trimZeroes && precision<displayPrecision) && precision!=displayPrecision {
-        if precision>displayPrecision {
-            x := slen+1-int(precision)+int(displayPrecision) // fix
-            if trimZeroes {
-                if x>i+1 { x = i+1 }
-                for x--; os[x]=='0'; x-- { }
-                x++
-            }
-            os = os[:x]
-        } else {
-            copy(os[l+1:], str[slen-int(precision):i])
-            for i:=0; i < int(displayPrecision-precision); i++ {
-                os = append(os, '0')
-            }
-        }
-    }
-    if len(os)==dotPos+1 {
-        os = append(os, '0')
-    }
-    return os
+    return a.FormatOptsBytes(precision, legacyFormatOptions(precision,
+                displayPrecision, trimZeroes))
+}
+
+// new format routine with rounding mode. Format to bytes
+func (a UDec64) FormatNewBytesR(precision, displayPrecision uint, trimZeroes bool,
+                                mode RoundingMode) []byte {
+    opts := legacyFormatOptions(precision, displayPrecision, trimZeroes)
+    opts.Rounding = mode
+    return a.FormatOptsBytes(precision, opts)
 }
 
 // format number to bytes
@@ -461,52 +445,316 @@ func ParseUDec64Bytes(str []byte, precision uint, rounding bool) (UDec64, error)
     return 0, nil
 }
 
-var float64_revpowers []float64 = []float64{
-    1,
-    0.1,
-    0.01,
-    0.001,
-    0.0001,
-    0.00001,
-    0.000001,
-    0.0000001,
-    0.00000001,
-    0.000000001,
-    0.0000000001,
-    0.00000000001,
-    0.000000000001,
-    0.0000000000001,
-    0.00000000000001,
-    0.000000000000001,
-    0.0000000000000001,
-    0.00000000000000001,
-    0.000000000000000001,
-}
-
-// convert to float64
+// get binary value of a/10^precision rounded (half to even) to mantBits
+// significant bits. Returns mantissa and exponent (value = mant*2^exp)
+func (a UDec64) toBinary(precision uint, mantBits int) (uint64, int) {
+    d := uint64_powers[precision]
+    // q = a*2^s/d, where q is in (2^62, 2^64), then a*2^s < 2^64*d
+    s := 63 + bits.Len64(d) - bits.Len64(uint64(a))
+    var hi, lo uint64
+    if s < 64 {
+        hi, lo = uint64(a)>>(64-uint(s)), uint64(a)<<uint(s)
+    } else {
+        hi, lo = uint64(a)<<uint(s-64), 0
+    }
+    q, r := bits.Div64(hi, lo, d)
+    // round to mantBits
+    drop := bits.Len64(q) - mantBits
+    m := q >> uint(drop)
+    rem := q & (1<<uint(drop)-1)
+    half := uint64(1)<<uint(drop-1)
+    if rem > half || (rem==half && (r!=0 || m&1!=0)) {
+        m++
+        if m==1<<uint(mantBits) {
+            m >>= 1
+            drop++
+        }
+    }
+    return m, drop-s
+}
+
+// convert to float64. Returns float64 nearest to exact decimal value
 func (a UDec64) ToFloat64(precision uint) float64 {
-    return float64(a)*float64_revpowers[precision]
+    if a < 1<<53 {
+        // both values are exact, division is correctly rounded
+        return float64(a)/float64(uint64_powers[precision])
+    }
+    m, e := a.toBinary(precision, 53)
+    return math.Ldexp(float64(m), e)
 }
 
-// convert float64 to UDec128
-func Float64ToUDec64(a float64, precision uint) (UDec64, error) {
-    if math.IsNaN(a) || a >= 18446744073709551616.0 || a < 0.0 {
-        return 0, strconv.ErrRange
+// convert to float32. Returns float32 nearest to exact decimal value
+func (a UDec64) ToFloat32(precision uint) float32 {
+    if a < 1<<24 && precision <= 10 {
+        // both values are exact, division is correctly rounded
+        return float32(a)/float32(uint64_powers[precision])
     }
-    return UDec64(float64(a)*float64(uint64_powers[precision])), nil
+    if a==0 { return 0 }
+    m, e := a.toBinary(precision, 24)
+    return float32(math.Ldexp(float64(m), e))
+}
+
+// convert float64 to UDec64 with legacy rounding mode. Exact binary value
+// of float64 is returned if it fits in precision, otherwise shortest decimal
+// representation of float64 is rounded to precision
+func float64ToUDec64Legacy(a float64, precision uint,
+                           mode RoundingMode) (UDec64, error) {
+    v, exact, err := float64ToUDec64Exact(a, precision, RoundDown)
+    if err==nil && exact { return v, nil }
+    return Float64ToUDec64Shortest(a, precision, mode)
+}
+
+// convert float64 to UDec64 (exact value if it fits in precision, otherwise
+// shortest decimal representation of float64 is truncated)
+func Float64ToUDec64(a float64, precision uint) (UDec64, error) {
+    return float64ToUDec64Legacy(a, precision, RoundDown)
 }
 
-// convert float64 to UDec128
+// convert float64 to UDec64 (exact value if it fits in precision, otherwise
+// shortest decimal representation of float64 is used).
+// If rounding is true then value will be rounded half to even
 func Float64ToUDec64R(a float64, precision uint, rounding bool) (UDec64, error) {
-    if math.IsNaN(a) || a >= 18446744073709551616.0 || a < 0.0 {
-        return 0, strconv.ErrRange
-    }
-    f := float64(a)*float64(uint64_powers[precision])
     if rounding {
-        return UDec64(math.RoundToEven(f)), nil
+        return float64ToUDec64Legacy(a, precision, RoundHalfEven)
+    }
+    return float64ToUDec64Legacy(a, precision, RoundDown)
+}
+
+// convert float64 to UDec64. Exact binary value of float64 will be rounded
+// to precision with given rounding mode
+func Float64ToUDec64Exact(a float64, precision uint, mode RoundingMode) (UDec64, error) {
+    v, _, err := float64ToUDec64Exact(a, precision, mode)
+    return v, err
+}
+
+// convert float64 to UDec64 rounding exact binary value, returns also true
+// if value is exact (no digits dropped)
+func float64ToUDec64Exact(a float64, precision uint,
+                          mode RoundingMode) (UDec64, bool, error) {
+    if math.IsNaN(a) || math.IsInf(a, 0) || a < 0.0 {
+        return 0, false, strconv.ErrRange
+    }
+    if a==0 { return 0, true, nil }
+    // decompose float64: value = mant*2^exp
+    fb := math.Float64bits(a)
+    exp := int((fb>>52)&0x7ff)
+    mant := fb & (1<<52-1)
+    if exp==0 {
+        exp = 1 // subnormal
+    } else {
+        mant |= 1<<52
+    }
+    exp -= 1075
+    // mant*10^precision - fits in 117 bits
+    hi, lo := bits.Mul64(mant, uint64_powers[precision])
+    if exp >= 0 {
+        if hi!=0 || bits.Len64(lo)+exp > 64 {
+            return 0, false, strconv.ErrRange
+        }
+        return UDec64(lo<<uint(exp)), true, nil
+    }
+    shift := uint(-exp)
+    if shift >= 128 {
+        // value lower than half
+        if roundUp(0, -1, false, mode) { return 1, false, nil }
+        return 0, false, nil
+    }
+    // quotient and remainder of division by 2^shift
+    var q uint64
+    var halfBit, sticky bool
+    if shift < 64 {
+        if hi>>shift!=0 { return 0, false, strconv.ErrRange }
+        q = lo>>shift | hi<<(64-shift)
+        halfBit = (lo>>(shift-1))&1!=0
+        sticky = lo&(1<<(shift-1)-1)!=0
     } else {
-        return UDec64(math.Floor(f)), nil
+        q = hi>>(shift-64)
+        if shift==64 {
+            halfBit = lo>>63!=0
+            sticky = lo&(1<<63-1)!=0
+        } else {
+            halfBit = (hi>>(shift-65))&1!=0
+            sticky = lo!=0 || hi&(1<<(shift-65)-1)!=0
+        }
+    }
+    half := -1
+    if halfBit {
+        half = 0
+        if sticky { half = 1 }
+    }
+    if roundUp(q, half, !halfBit && !sticky, mode) {
+        q++
+        if q==0 { return 0, false, strconv.ErrRange }
+    }
+    return UDec64(q), !halfBit && !sticky, nil
+}
+
+// convert float64 to UDec64. Shortest decimal representation of float64
+// (that parses back to same float64) will be rounded to precision
+// with given rounding mode
+func Float64ToUDec64Shortest(a float64, precision uint,
+                             mode RoundingMode) (UDec64, error) {
+    if math.IsNaN(a) || math.IsInf(a, 0) || a < 0.0 {
+        return 0, strconv.ErrRange
+    }
+    if a==0 { return 0, nil }
+    var buf [32]byte
+    s := strconv.AppendFloat(buf[:0], a, 'e', -1, 64)
+    // get digits and exponent
+    var digits [20]byte
+    n := 0
+    i := 0
+    for ; s[i]!='e'; i++ {
+        if s[i]!='.' {
+            digits[n] = s[i]-'0'
+            n++
+        }
+    }
+    i++
+    expNeg := s[i]=='-'
+    exp := 0
+    for i++; i < len(s); i++ {
+        exp = exp*10 + int(s[i]-'0')
+    }
+    if expNeg { exp = -exp }
+    return digitsToUDec64(digits[:n], exp-(n-1), precision, mode)
+}
+
+// multiply a by 10^k, returns false if overflow
+func mulPow10(a uint64, k uint) (uint64, bool) {
+    if a==0 { return 0, true }
+    for ; k >= 18; k -= 18 {
+        hi, lo := bits.Mul64(a, uint64_powers[18])
+        if hi!=0 { return 0, false }
+        a = lo
+    }
+    hi, lo := bits.Mul64(a, uint64_powers[k])
+    return lo, hi==0
+}
+
+// convert coefficient multiplied by 10^exp to UDec64 with precision
+// and rounding mode
+func coefToUDec64(c uint64, exp int, precision uint,
+                  mode RoundingMode) (UDec64, error) {
+    k := exp + int(precision) // value = c*10^k
+    if k >= 0 {
+        v, ok := mulPow10(c, uint(k))
+        if !ok { return 0, strconv.ErrRange }
+        return UDec64(v), nil
     }
+    return UDec64(divPow10(c, uint(-k), mode)), nil
+}
+
+// convert decimal digits (values 0-9) multiplied by 10^exp to UDec64
+// with precision and rounding mode
+func digitsToUDec64(digits []byte, exp int, precision uint,
+                    mode RoundingMode) (UDec64, error) {
+    k := exp + int(precision) // value = digits*10^k
+    n := len(digits)
+    intLen := n
+    if k < 0 { intLen = n+k }
+    var v uint64
+    for i:=0; i < intLen; i++ {
+        hi, lo := bits.Mul64(v, 10)
+        var carry uint64
+        lo, carry = bits.Add64(lo, uint64(digits[i]), 0)
+        if hi!=0 || carry!=0 { return 0, strconv.ErrRange }
+        v = lo
+    }
+    if k > 0 && v!=0 {
+        for ; k > 0; k-- {
+            hi, lo := bits.Mul64(v, 10)
+            if hi!=0 { return 0, strconv.ErrRange }
+            v = lo
+        }
+    } else if k < 0 {
+        // rounding
+        half := -1
+        exact := true
+        if intLen >= 0 {
+            sticky := false
+            for i:=intLen+1; i < n; i++ {
+                if digits[i]!=0 {
+                    sticky = true
+                    break
+                }
+            }
+            rd := digits[intLen]
+            if rd > 5 || (rd==5 && sticky) {
+                half = 1
+            } else if rd==5 {
+                half = 0
+            }
+            exact = rd==0 && !sticky
+        } else {
+            for _, d := range digits {
+                if d!=0 {
+                    exact = false
+                    break
+                }
+            }
+        }
+        if roundUp(v, half, exact, mode) {
+            v++
+            if v==0 { return 0, strconv.ErrRange }
+        }
+    }
+    return UDec64(v), nil
+}
+
+// append decimal digits (values 0-9) of v with precision to dst, aligned to
+// groups of n digits at both sides of comma. Leading and trailing zero groups
+// are skipped. Returns digits and weight (exponent in groups) of first group
+func groupedDigits(dst []byte, v uint64, precision uint, n int) ([]byte, int) {
+    if v==0 { return dst, 0 }
+    var buf [24]byte
+    s := strconv.AppendUint(buf[:0], v, 10)
+    // exponent of first and last digit rounded to group boundaries
+    hiExp := len(s)-1-int(precision)
+    loExp := -int(precision)
+    for ; s[len(s)-1]=='0'; s = s[:len(s)-1] {
+        loExp++
+    }
+    weight := floorDiv(hiExp, n)
+    lastGroup := floorDiv(loExp, n)
+    for e:=weight*n+n-1; e > hiExp; e-- {
+        dst = append(dst, 0)
+    }
+    for _, c := range s {
+        dst = append(dst, c-'0')
+    }
+    for e:=loExp-1; e >= lastGroup*n; e-- {
+        dst = append(dst, 0)
+    }
+    return dst, weight
+}
+
+// place decimal digits (values 0-9) of v with precision in dst that holds
+// intDigits digits before comma and at least precision digits after comma.
+// Returns false if v has too many digits in integer part
+func fixedDigits(dst []byte, v uint64, precision uint, intDigits int) bool {
+    for i := range dst {
+        dst[i] = 0
+    }
+    var buf [24]byte
+    s := strconv.AppendUint(buf[:0], v, 10)
+    for j:=0; j < len(s); j++ {
+        e := len(s)-1-j-int(precision) // exponent of digit
+        pos := intDigits-1-e
+        if pos < 0 {
+            if s[j]!='0' { return false }
+            continue
+        }
+        dst[pos] = s[j]-'0'
+    }
+    return true
+}
+
+// integer division rounded towards minus infinity
+func floorDiv(a, b int) int {
+    q := a/b
+    if a%b!=0 && a<0 { q-- }
+    return q
 }
 
 func (a UDec64) Convert(srcPrec, destPrec uint, rounding bool) UDec64 {
@@ -23,6 +23,7 @@
 package godec64
  
 import (
+    "math"
     "strconv"
     "testing"
 )
@@ -112,6 +113,9 @@ func TestUDec64Format(t *testing.T) {
         UDec64FmtTC{ 1984593924000, 15, false, "0.001984593924000" },
         UDec64FmtTC{ 1984593924000, 15, true, "0.001984593924" },
         UDec64FmtTC{ 0, 15, true, "0.0" },
+        UDec64FmtTC{ 0, 15, false, "0.000000000000000" },
+        UDec64FmtTC{ 0, 2, false, "0.00" },
+        UDec64FmtTC{ 0, 0, false, "0" },
         UDec64FmtTC{ 1, 15, false, "0.000000000000001" },
         UDec64FmtTC{ 3211984593924556, 15, false, "3.211984593924556" },
         UDec64FmtTC{ 33000000000000000, 15, false, "33.000000000000000" },
@@ -151,6 +155,19 @@ func TestUDec64Format(t *testing.T) {
         UDec64Fmt2TC{ 425143693331510191, 15, 12, true, "425.14369333151" },
         UDec64Fmt2TC{ 425143693331510200, 15, 12, true, "425.14369333151" },
         UDec64Fmt2TC{ 425143693331510000, 15, 13, true, "425.14369333151" },
+        UDec64Fmt2TC{ 0, 15, 2, false, "0.00" },
+        UDec64Fmt2TC{ 0, 2, 4, false, "0.0000" },
+        UDec64Fmt2TC{ 0, 2, 4, true, "0.0" },
+        UDec64Fmt2TC{ 1984593924556, 15, 4, false, "0.0019" },
+        UDec64Fmt2TC{ 1984593924556, 15, 2, true, "0.0" },
+        UDec64Fmt2TC{ 425, 0, 2, false, "425.00" },
+        // zero display precision: legacy routines print one digit after comma
+        UDec64Fmt2TC{ 12300, 2, 0, false, "123.0" },
+        UDec64Fmt2TC{ 12345, 2, 0, false, "123.0" },
+        UDec64Fmt2TC{ 12345, 2, 0, true, "123.0" },
+        UDec64Fmt2TC{ 0, 2, 0, false, "0.0" },
+        UDec64Fmt2TC{ 425, 0, 0, false, "425" },
+        UDec64Fmt2TC{ 0, 0, 0, false, "0" },
     }
     for i, tc := range testCases2 {
         a := tc.a
@@ -173,6 +190,98 @@ func TestUDec64Format(t *testing.T) {
     }
 }
 
+type UDec64FmtRTC struct {
+    a UDec64
+    precision uint
+    dispPrecision uint
+    trimZeroes bool
+    mode RoundingMode
+    expected string
+}
+
+func TestUDec64FormatR(t *testing.T) {
+    testCases := []UDec64FmtRTC {
+        UDec64FmtRTC{ 1995, 3, 2, false, RoundDown, "1.99" },
+        UDec64FmtRTC{ 1995, 3, 2, false, RoundHalfUp, "2.00" },
+        UDec64FmtRTC{ 1995, 3, 2, true, RoundHalfUp, "2.0" },
+        UDec64FmtRTC{ 1995, 3, 2, false, RoundHalfEven, "2.00" },
+        UDec64FmtRTC{ 1985, 3, 2, false, RoundHalfEven, "1.98" },
+        UDec64FmtRTC{ 1985, 3, 2, false, RoundHalfDown, "1.98" },
+        UDec64FmtRTC{ 1986, 3, 2, false, RoundHalfDown, "1.99" },
+        UDec64FmtRTC{ 1981, 3, 2, false, RoundUp, "1.99" },
+        UDec64FmtRTC{ 1980, 3, 2, false, RoundUp, "1.98" },
+        UDec64FmtRTC{ 9999, 3, 2, false, RoundHalfUp, "10.00" },
+        UDec64FmtRTC{ 999999, 5, 2, false, RoundHalfUp, "10.00" },
+        UDec64FmtRTC{ 999999, 5, 0, false, RoundHalfUp, "10.0" },
+        UDec64FmtRTC{ 4999, 3, 0, false, RoundHalfUp, "5.0" },
+        UDec64FmtRTC{ 5, 3, 2, false, RoundHalfUp, "0.01" },
+        UDec64FmtRTC{ 425143693331510191, 15, 12, false, RoundHalfUp, "425.143693331510" },
+        UDec64FmtRTC{ 425143693331510191, 15, 13, false, RoundHalfUp, "425.1436933315102" },
+        UDec64FmtRTC{ 425143693331510191, 15, 17, false, RoundHalfUp, "425.14369333151019100" },
+        UDec64FmtRTC{ 0xffffffffffffffff, 2, 0, false, RoundHalfUp, "184467440737095516.0" },
+        UDec64FmtRTC{ 0xffffffffffffffff, 0, 0, false, RoundHalfUp, "18446744073709551615" },
+    }
+    for i, tc := range testCases {
+        a := tc.a
+        result := tc.a.FormatNewR(tc.precision, tc.dispPrecision, tc.trimZeroes, tc.mode)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v)->%v!=%v",
+                     i, tc.a, tc.expected, result)
+        }
+        if tc.a!=a {
+            t.Errorf("Argument has been modified: %d: %v!=%v", i, a, tc.a)
+        }
+        resultBytes := tc.a.FormatNewBytesR(tc.precision, tc.dispPrecision,
+                                            tc.trimZeroes, tc.mode)
+        if tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytes(%v)->%v!=%v",
+                     i, tc.a, tc.expected, string(resultBytes))
+        }
+    }
+}
+
+// rounding variants with RoundDown give same result as legacy routines
+func TestUDec64FormatRLegacy(t *testing.T) {
+    testCases := []UDec64Fmt2TC {
+        UDec64Fmt2TC{ 12345, 2, 0, false, "123.0" },
+        UDec64Fmt2TC{ 12345, 2, 0, true, "123.0" },
+        UDec64Fmt2TC{ 0, 2, 0, false, "0.0" },
+        UDec64Fmt2TC{ 0, 0, 0, false, "0" },
+        UDec64Fmt2TC{ 425, 0, 0, false, "425" },
+        UDec64Fmt2TC{ 425, 0, 2, false, "425.00" },
+        UDec64Fmt2TC{ 1984593924556, 15, 4, false, "0.0019" },
+        UDec64Fmt2TC{ 1984593924556, 15, 2, true, "0.0" },
+        UDec64Fmt2TC{ 425143693331510191, 15, 17, true, "425.143693331510191" },
+    }
+    for i, tc := range testCases {
+        expected := tc.a.FormatNew(tc.precision, tc.dispPrecision, tc.trimZeroes)
+        if tc.expected!=expected {
+            t.Errorf("Result mismatch: %d: fmt(%v)->%v!=%v",
+                     i, tc.a, tc.expected, expected)
+        }
+        result := tc.a.FormatNewR(tc.precision, tc.dispPrecision, tc.trimZeroes,
+                                  RoundDown)
+        if expected!=result {
+            t.Errorf("Result mismatch: %d: fmtR(%v)->%v!=%v",
+                     i, tc.a, expected, result)
+        }
+        resultBytes := tc.a.FormatNewBytesR(tc.precision, tc.dispPrecision,
+                                            tc.trimZeroes, RoundDown)
+        if expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytesR(%v)->%v!=%v",
+                     i, tc.a, expected, string(resultBytes))
+        }
+        expected = tc.a.LocaleFormatNew("de", tc.precision, tc.dispPrecision,
+                                        tc.trimZeroes, false)
+        result = tc.a.LocaleFormatNewR("de", tc.precision, tc.dispPrecision,
+                                       tc.trimZeroes, false, RoundDown)
+        if expected!=result {
+            t.Errorf("Result mismatch: %d: locFmtR(%v)->%v!=%v",
+                     i, tc.a, expected, result)
+        }
+    }
+}
+
 type UDec64ParseTC struct {
     str string
     precision uint
@@ -269,8 +378,16 @@ func TestUDec64ToFloat64(t *testing.T) {
         UDec64ToFloat64TC{ 54930201, 11, 54930201.0*1e-11 },
         UDec64ToFloat64TC{ 85959028918918968, 0, 85959028918918968.0 },
         UDec64ToFloat64TC{ 85959028918918968, 11, 85959028918918968.0*1e-11 },
-        UDec64ToFloat64TC{ 85959028918918968, 17, 0.8595902891891898 },
+        UDec64ToFloat64TC{ 85959028918918968, 17, 0.85959028918918968 },
         UDec64ToFloat64TC{ 0xffffffffffffffff, 11, 18446744073709551615.0*1e-11 },
+        UDec64ToFloat64TC{ 0xffffffffffffffff, 0, 18446744073709551615.0 },
+        UDec64ToFloat64TC{ 0xffffffffffffffff, 18, 18.446744073709551615 },
+        UDec64ToFloat64TC{ 29, 2, 0.29 },
+        UDec64ToFloat64TC{ 9007199254740993, 0, 9007199254740992.0 },
+        UDec64ToFloat64TC{ 9007199254740995, 0, 9007199254740996.0 },
+        UDec64ToFloat64TC{ 9007199254740993, 16, 0.9007199254740993 },
+        UDec64ToFloat64TC{ 123456789012345678, 18, 0.123456789012345678 },
+        UDec64ToFloat64TC{ 1, 18, 1e-18 },
     }
     for i, tc := range testCases {
         result := tc.value.ToFloat64(tc.precision)
@@ -281,6 +398,95 @@ func TestUDec64ToFloat64(t *testing.T) {
     }
 }
 
+type UDec64ToFloat32TC struct {
+    value UDec64
+    precision uint
+    expected float32
+}
+
+func TestUDec64ToFloat32(t *testing.T) {
+    testCases := []UDec64ToFloat32TC{
+        UDec64ToFloat32TC{ 0, 11, 0.0 },
+        UDec64ToFloat32TC{ 1, 11, 1e-11 },
+        UDec64ToFloat32TC{ 29, 2, 0.29 },
+        UDec64ToFloat32TC{ 16777217, 0, 16777216.0 },
+        UDec64ToFloat32TC{ 16777219, 0, 16777220.0 },
+        UDec64ToFloat32TC{ 54930201, 11, 54930201.0*1e-11 },
+        UDec64ToFloat32TC{ 85959028918918968, 17, 0.85959028918918968 },
+        UDec64ToFloat32TC{ 0xffffffffffffffff, 11, 18446744073709551615.0*1e-11 },
+        UDec64ToFloat32TC{ 0xffffffffffffffff, 0, 18446744073709551615.0 },
+        UDec64ToFloat32TC{ 1, 18, 1e-18 },
+    }
+    for i, tc := range testCases {
+        result := tc.value.ToFloat32(tc.precision)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: tofloat32(%v,%v)->%v!=%v",
+                     i, tc.value, tc.precision, tc.expected, result)
+        }
+    }
+}
+
+func TestUDec64ToFloatParse(t *testing.T) {
+    // compare with strconv.ParseFloat that is correctly rounded
+    v := uint64(1)
+    for i:=0; i < 20000; i++ {
+        v = v*6364136223846793005 + 1442695040888963407
+        a := UDec64(v >> (uint(i)%64))
+        precision := uint(i)%19
+        str := a.Format(precision, false)
+        expected, _ := strconv.ParseFloat(str, 64)
+        if result := a.ToFloat64(precision); expected!=result {
+            t.Errorf("Result mismatch: %d: tofloat64(%v,%v)->%v!=%v",
+                     i, a, precision, expected, result)
+        }
+        expected32, _ := strconv.ParseFloat(str, 32)
+        if result := a.ToFloat32(precision); float32(expected32)!=result {
+            t.Errorf("Result mismatch: %d: tofloat32(%v,%v)->%v!=%v",
+                     i, a, precision, float32(expected32), result)
+        }
+    }
+}
+
+func TestUDec64FloatRoundTrip(t *testing.T) {
+    // float64 -> UDec64 -> float64
+    floats := []float64{ 0.1, 0.2, 0.29, 0.3, 1.005, 3.14159, 2.718281828459045,
+        123456.789, 9007199254740993.0, 1e-10, 0.1234567890123456,
+        18446744073709549568.0, 5.859030345539292e+07, 1.7976931348623157e-5 }
+    for i, f := range floats {
+        for precision := uint(0); precision <= 18; precision++ {
+            str := strconv.FormatFloat(f, 'f', -1, 64)
+            fracLen := 0
+            for k := len(str)-1; k>=0; k-- {
+                if str[k]=='.' {
+                    fracLen = len(str)-k-1
+                    break
+                }
+            }
+            a, err := Float64ToUDec64Shortest(f, precision, RoundHalfEven)
+            if err!=nil || fracLen > int(precision) {
+                continue // not representable
+            }
+            if result := a.ToFloat64(precision); f!=result {
+                t.Errorf("Round trip mismatch: %d: %v,%v->%v->%v",
+                         i, f, precision, a, result)
+            }
+        }
+    }
+    // UDec64 -> float64 -> UDec64 for values with 15 significant digits
+    v := uint64(7)
+    for i:=0; i < 20000; i++ {
+        v = v*6364136223846793005 + 1442695040888963407
+        a := UDec64((v>>14) % 1000000000000000)
+        precision := uint(i)%19
+        f := a.ToFloat64(precision)
+        result, err := Float64ToUDec64Shortest(f, precision, RoundHalfEven)
+        if a!=result || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%v->%v,%v",
+                     i, a, precision, f, result, err)
+        }
+    }
+}
+
 type Float64ToUDec64TC struct {
     value float64
     precision uint
@@ -297,8 +503,17 @@ func TestFloat64ToUDec64(t *testing.T) {
         Float64ToUDec64TC{ 3145645677.778, 0, 3145645677, nil },
         Float64ToUDec64TC{ 187923786919586921.0, 0, 187923786919586912, nil },
         Float64ToUDec64TC{ 11792378691958692154.0, 0, 11792378691958691840, nil },
+        Float64ToUDec64TC{ 144115188075855877.0, 0, 144115188075855872, nil },
+        Float64ToUDec64TC{ 0.125, 3, 125, nil },
         Float64ToUDec64TC{ 145645677.18, 3, 145645677180, nil },
-        Float64ToUDec64TC{ 58590303.45539292211, 11, 0x514f750e8a1a8c00, nil },
+        Float64ToUDec64TC{ 58590303.45539292211, 11, 5859030345539292000, nil },
+        Float64ToUDec64TC{ 0.29, 2, 29, nil },
+        Float64ToUDec64TC{ 0.57, 2, 57, nil },
+        Float64ToUDec64TC{ 1.15, 2, 115, nil },
+        Float64ToUDec64TC{ 4.35, 2, 435, nil },
+        Float64ToUDec64TC{ 1844674407370955.0, 4, 18446744073709550000, nil },
+        Float64ToUDec64TC{ 1844674407370955.25, 4, 0, strconv.ErrRange },
+        Float64ToUDec64TC{ 18446744073709.56, 6, 0, strconv.ErrRange },
         Float64ToUDec64TC{ -1.0, 0, 0, strconv.ErrRange },
         Float64ToUDec64TC{ 18446744073709551616.0, 0, 0, strconv.ErrRange },
         Float64ToUDec64TC{ 18446744073709551617.0, 0, 0, strconv.ErrRange },
@@ -331,10 +546,13 @@ func TestFloat64ToUDec64R(t *testing.T) {
         Float64ToUDec64RTC{ 3145645677.778, 0, true, 3145645678, nil },
         Float64ToUDec64RTC{ 187923786919586921.0, 0, false, 187923786919586912, nil },
         Float64ToUDec64RTC{ 11792378691958692154.0, 0, false, 11792378691958691840, nil },
+        Float64ToUDec64RTC{ 187923786919586921.0, 0, true, 187923786919586912, nil },
+        Float64ToUDec64RTC{ 0.125, 2, true, 12, nil },
+        Float64ToUDec64RTC{ 0.29, 2, true, 29, nil },
         Float64ToUDec64RTC{ 145645677.18, 3, false, 145645677180, nil },
         Float64ToUDec64RTC{ 145645677.1807, 3, false, 145645677180, nil },
         Float64ToUDec64RTC{ 145645677.1807, 3, true, 145645677181, nil },
-        Float64ToUDec64RTC{ 58590303.45539292211, 11, false, 0x514f750e8a1a8c00, nil },
+        Float64ToUDec64RTC{ 58590303.45539292211, 11, false, 5859030345539292000, nil },
         Float64ToUDec64RTC{ -1.0, 0, false, 0, strconv.ErrRange },
         Float64ToUDec64RTC{ 18446744073709551616.0, 0, false, 0, strconv.ErrRange },
         Float64ToUDec64RTC{ 18446744073709551617.0, 0, false, 0, strconv.ErrRange },
@@ -348,6 +566,100 @@ func TestFloat64ToUDec64R(t *testing.T) {
     }
 }
 
+type Float64ToUDec64ModeTC struct {
+    value float64
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestFloat64ToUDec64Exact(t *testing.T) {
+    testCases := []Float64ToUDec64ModeTC{
+        Float64ToUDec64ModeTC{ 0.0, 2, RoundDown, 0, nil },
+        Float64ToUDec64ModeTC{ 0.29, 2, RoundDown, 28, nil },
+        Float64ToUDec64ModeTC{ 0.29, 2, RoundHalfEven, 29, nil },
+        Float64ToUDec64ModeTC{ 0.29, 2, RoundUp, 29, nil },
+        Float64ToUDec64ModeTC{ 0.5, 0, RoundHalfEven, 0, nil },
+        Float64ToUDec64ModeTC{ 1.5, 0, RoundHalfEven, 2, nil },
+        Float64ToUDec64ModeTC{ 2.5, 0, RoundHalfEven, 2, nil },
+        Float64ToUDec64ModeTC{ 2.5, 0, RoundHalfUp, 3, nil },
+        Float64ToUDec64ModeTC{ 2.5, 0, RoundHalfDown, 2, nil },
+        Float64ToUDec64ModeTC{ 2.5, 0, RoundDown, 2, nil },
+        // 1.005 is 1.00499999999999989...
+        Float64ToUDec64ModeTC{ 1.005, 2, RoundHalfUp, 100, nil },
+        Float64ToUDec64ModeTC{ 187923786919586921.0, 0, RoundDown,
+                187923786919586912, nil },
+        Float64ToUDec64ModeTC{ 11792378691958692154.0, 0, RoundDown,
+                11792378691958691840, nil },
+        Float64ToUDec64ModeTC{ 58590303.45539292211, 11, RoundDown,
+                5859030345539291948, nil },
+        Float64ToUDec64ModeTC{ 58590303.45539292211, 11, RoundHalfUp,
+                5859030345539291948, nil },
+        Float64ToUDec64ModeTC{ 58590303.45539292211, 11, RoundUp,
+                5859030345539291949, nil },
+        Float64ToUDec64ModeTC{ 0.00001, 18, RoundDown, 10000000000000, nil },
+        Float64ToUDec64ModeTC{ 0.00001, 18, RoundUp, 10000000000001, nil },
+        Float64ToUDec64ModeTC{ 0.0003, 18, RoundDown, 299999999999999, nil },
+        Float64ToUDec64ModeTC{ 0.0003, 18, RoundHalfUp, 300000000000000, nil },
+        Float64ToUDec64ModeTC{ 5e-324, 18, RoundDown, 0, nil },
+        Float64ToUDec64ModeTC{ 5e-324, 18, RoundUp, 1, nil },
+        Float64ToUDec64ModeTC{ 1e-19, 18, RoundHalfUp, 0, nil },
+        Float64ToUDec64ModeTC{ 6e-19, 18, RoundHalfUp, 1, nil },
+        Float64ToUDec64ModeTC{ 18446744073709549568.0, 0, RoundDown,
+                18446744073709549568, nil },
+        Float64ToUDec64ModeTC{ 18446744073709551616.0, 0, RoundDown,
+                0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ 184467440737095.52, 5, RoundDown,
+                0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ -0.5, 0, RoundDown, 0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ math.Inf(1), 0, RoundDown, 0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ math.NaN(), 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        result, err := Float64ToUDec64Exact(tc.value, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: toudec64exact(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestFloat64ToUDec64Shortest(t *testing.T) {
+    testCases := []Float64ToUDec64ModeTC{
+        Float64ToUDec64ModeTC{ 0.0, 2, RoundDown, 0, nil },
+        Float64ToUDec64ModeTC{ 0.29, 2, RoundDown, 29, nil },
+        Float64ToUDec64ModeTC{ 0.29, 1, RoundDown, 2, nil },
+        Float64ToUDec64ModeTC{ 0.29, 1, RoundHalfUp, 3, nil },
+        Float64ToUDec64ModeTC{ 1.005, 2, RoundHalfUp, 101, nil },
+        Float64ToUDec64ModeTC{ 1.005, 2, RoundHalfEven, 100, nil },
+        Float64ToUDec64ModeTC{ 1.015, 2, RoundHalfEven, 102, nil },
+        Float64ToUDec64ModeTC{ 1.015, 2, RoundHalfDown, 101, nil },
+        Float64ToUDec64ModeTC{ 1.0151, 2, RoundHalfDown, 102, nil },
+        Float64ToUDec64ModeTC{ 1.011, 2, RoundUp, 102, nil },
+        Float64ToUDec64ModeTC{ 123456.789, 18, RoundDown, 0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ 12.789, 18, RoundDown, 12789000000000000000, nil },
+        Float64ToUDec64ModeTC{ 1e-30, 18, RoundDown, 0, nil },
+        Float64ToUDec64ModeTC{ 1e-30, 18, RoundUp, 1, nil },
+        Float64ToUDec64ModeTC{ 1e-30, 18, RoundHalfUp, 0, nil },
+        Float64ToUDec64ModeTC{ 5e-19, 18, RoundHalfUp, 1, nil },
+        Float64ToUDec64ModeTC{ 5e-19, 18, RoundHalfEven, 0, nil },
+        Float64ToUDec64ModeTC{ 1e19, 0, RoundDown, 10000000000000000000, nil },
+        Float64ToUDec64ModeTC{ 1e20, 0, RoundDown, 0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ 1e300, 0, RoundDown, 0, strconv.ErrRange },
+        Float64ToUDec64ModeTC{ math.Inf(1), 0, RoundDown, 0, strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        result, err := Float64ToUDec64Shortest(tc.value, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: toudec64shortest(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
 type ConvertUDec64TC struct {
     value UDec64
     srcPrecision uint
@@ -0,0 +1,152 @@
+/*
+ * decimal128.go - IEEE 754-2008 decimal128 BID encoding and BSON Decimal128
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "encoding/binary"
+    "math/bits"
+    "strconv"
+)
+
+const (
+    decimal128Bias = 6176
+    // 10^34-1 (maximal coefficient)
+    decimal128MaxCoefHi = 0x1ed09bead87c0
+    decimal128MaxCoefLo = 0x378d8e63ffffffff
+)
+
+// divide 128-bit value by 10^k with rounding, returns false if result
+// does not fit in 64 bits
+func div128Pow10(hi, lo uint64, k uint, mode RoundingMode) (uint64, bool) {
+    if k==0 {
+        return lo, hi==0
+    }
+    // divide by 10^(k-1) and remember if remainder is not zero
+    sticky := false
+    for k--; k > 0 && (hi!=0 || lo!=0); {
+        j := k
+        if j > 18 { j = 18 }
+        d := uint64_powers[j]
+        var r uint64
+        hi, r = hi/d, hi%d
+        lo, r = bits.Div64(r, lo, d)
+        sticky = sticky || r!=0
+        k -= j
+    }
+    if k!=0 {
+        // value is lower than 10^(k-1)
+        if roundUp(0, -1, !sticky, mode) { return 1, true }
+        return 0, true
+    }
+    // last digit is rounding digit
+    var rd uint64
+    hi, rd = hi/10, hi%10
+    lo, rd = bits.Div64(rd, lo, 10)
+    if hi!=0 {
+        return 0, false
+    }
+    half := -1
+    if rd > 5 || (rd==5 && sticky) {
+        half = 1
+    } else if rd==5 {
+        half = 0
+    }
+    if roundUp(lo, half, rd==0 && !sticky, mode) {
+        lo++
+        if lo==0 { return 0, false }
+    }
+    return lo, true
+}
+
+// returns true if decimal128 value is NaN. NaN is determined only by
+// high 64 bits (hi)
+func Decimal128IsNaN(hi uint64) bool {
+    return (hi>>58)&0x1f==0x1f
+}
+
+// returns true if decimal128 value is infinity. Infinity is determined only
+// by high 64 bits (hi)
+func Decimal128IsInf(hi uint64) bool {
+    return (hi>>58)&0x1f==0x1e
+}
+
+// convert to decimal128 in BID (Binary Integer Decimal) encoding.
+// Returns high and low 64 bits
+func (a UDec64) ToDecimal128BID(precision uint) (uint64, uint64) {
+    bexp := uint64(decimal128Bias-int(precision))
+    return bexp<<49, uint64(a)
+}
+
+// convert decimal128 in BID (Binary Integer Decimal) encoding (high and low
+// 64 bits) to UDec64 with precision and rounding mode
+func Decimal128BIDToUDec64(hi, lo uint64, precision uint,
+                           mode RoundingMode) (UDec64, error) {
+    if Decimal128IsNaN(hi) { return 0, ErrNaN }
+    if Decimal128IsInf(hi) { return 0, ErrInfinity }
+    var bexp int
+    var chi, clo uint64
+    if (hi>>61)&3!=3 {
+        bexp = int((hi>>49)&0x3fff)
+        chi, clo = hi&(1<<49-1), lo
+        if chi > decimal128MaxCoefHi ||
+            (chi==decimal128MaxCoefHi && clo > decimal128MaxCoefLo) {
+            chi, clo = 0, 0 // non-canonical
+        }
+    } else {
+        // coefficient is always greater than 10^34-1: non-canonical
+        bexp = int((hi>>47)&0x3fff)
+    }
+    if (chi!=0 || clo!=0) && hi>>63!=0 {
+        return 0, strconv.ErrRange // negative
+    }
+    k := bexp - decimal128Bias + int(precision) // value = c*10^k
+    if k >= 0 {
+        if chi!=0 { return 0, strconv.ErrRange }
+        v, ok := mulPow10(clo, uint(k))
+        if !ok { return 0, strconv.ErrRange }
+        return UDec64(v), nil
+    }
+    v, ok := div128Pow10(chi, clo, uint(-k), mode)
+    if !ok { return 0, strconv.ErrRange }
+    return UDec64(v), nil
+}
+
+// convert to BSON Decimal128 (decimal128 BID, little endian)
+func (a UDec64) ToBSONDecimal128(precision uint) [16]byte {
+    var out [16]byte
+    hi, lo := a.ToDecimal128BID(precision)
+    binary.LittleEndian.PutUint64(out[:8], lo)
+    binary.LittleEndian.PutUint64(out[8:], hi)
+    return out
+}
+
+// convert BSON Decimal128 (decimal128 BID, little endian, 16 bytes) to UDec64
+// with precision and rounding mode
+func BSONDecimal128ToUDec64(b []byte, precision uint,
+                            mode RoundingMode) (UDec64, error) {
+    if len(b)!=16 {
+        return 0, strconv.ErrSyntax
+    }
+    return Decimal128BIDToUDec64(binary.LittleEndian.Uint64(b[8:]),
+                    binary.LittleEndian.Uint64(b[:8]), precision, mode)
+}
@@ -0,0 +1,138 @@
+/*
+ * decimal128_test.go - IEEE 754-2008 decimal128 and BSON Decimal128 tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToBSONTC struct {
+    value UDec64
+    precision uint
+    expected string
+}
+
+func TestUDec64ToBSONDecimal128(t *testing.T) {
+    testCases := []UDec64ToBSONTC {
+        UDec64ToBSONTC{ 0, 0, "00000000000000000000000000004030" },
+        UDec64ToBSONTC{ 1, 0, "01000000000000000000000000004030" },
+        UDec64ToBSONTC{ 1, 1, "01000000000000000000000000003e30" },
+        UDec64ToBSONTC{ 1234, 6, "d2040000000000000000000000003430" },
+        UDec64ToBSONTC{ 0xffffffffffffffff, 18,
+                "ffffffffffffffff0000000000001c30" },
+    }
+    for i, tc := range testCases {
+        result := tc.value.ToBSONDecimal128(tc.precision)
+        if tc.expected!=hex.EncodeToString(result[:]) {
+            t.Errorf("Result mismatch: %d: tobson(%v,%v)->%v!=%x",
+                     i, tc.value, tc.precision, tc.expected, result)
+        }
+        back, err := BSONDecimal128ToUDec64(result[:], tc.precision, RoundDown)
+        if back!=tc.value || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%x->%v,%v",
+                     i, tc.value, tc.precision, result, back, err)
+        }
+    }
+}
+
+type BSONToUDec64TC struct {
+    value string
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestBSONDecimal128ToUDec64(t *testing.T) {
+    testCases := []BSONToUDec64TC {
+        // "0", "-0", "0E+3"
+        BSONToUDec64TC{ "00000000000000000000000000004030", 2, RoundDown, 0, nil },
+        BSONToUDec64TC{ "000000000000000000000000000040b0", 2, RoundDown, 0, nil },
+        BSONToUDec64TC{ "00000000000000000000000000004630", 2, RoundDown, 0, nil },
+        // "1", "0.1", "0.001234", "123456789012"
+        BSONToUDec64TC{ "01000000000000000000000000004030", 2, RoundDown, 100, nil },
+        BSONToUDec64TC{ "01000000000000000000000000003e30", 2, RoundDown, 10, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 6, RoundDown, 1234, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 4, RoundDown, 12, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 4, RoundHalfUp, 12, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 5, RoundHalfUp, 123, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 5, RoundHalfEven, 123, nil },
+        BSONToUDec64TC{ "d2040000000000000000000000003430", 3, RoundUp, 2, nil },
+        BSONToUDec64TC{ "141a99be1c0000000000000000004030", 0, RoundDown,
+                123456789012, nil },
+        // "1E+3", "1E+20"
+        BSONToUDec64TC{ "01000000000000000000000000004630", 2, RoundDown, 100000, nil },
+        BSONToUDec64TC{ "01000000000000000000000000006830", 0, RoundDown,
+                0, strconv.ErrRange },
+        // 10^34-1 with exponent -34 ("0.9999999999999999999999999999999999")
+        BSONToUDec64TC{ "ffffffff638e8d37c087adbe09edfd2f", 18, RoundDown,
+                999999999999999999, nil },
+        BSONToUDec64TC{ "ffffffff638e8d37c087adbe09edfd2f", 18, RoundHalfUp,
+                1000000000000000000, nil },
+        BSONToUDec64TC{ "ffffffff638e8d37c087adbe09edfd2f", 0, RoundHalfUp, 1, nil },
+        BSONToUDec64TC{ "ffffffff638e8d37c087adbe09edfd2f", 0, RoundDown, 0, nil },
+        // 10^34-1 with exponent -10: too big
+        BSONToUDec64TC{ "ffffffff638e8d37c087adbe09ed2d30", 0, RoundDown,
+                0, strconv.ErrRange },
+        // "1E-6176"
+        BSONToUDec64TC{ "01000000000000000000000000000000", 18, RoundDown, 0, nil },
+        BSONToUDec64TC{ "01000000000000000000000000000000", 18, RoundUp, 1, nil },
+        // non-canonical (coefficient too big)
+        BSONToUDec64TC{ "ffffffffffffffffffffffffffff4130", 0, RoundDown, 0, nil },
+        BSONToUDec64TC{ "000000000000000000000000000000ec", 0, RoundDown, 0, nil },
+        // "-1"
+        BSONToUDec64TC{ "010000000000000000000000000040b0", 2, RoundDown,
+                0, strconv.ErrRange },
+        // "Infinity", "-Infinity", "NaN"
+        BSONToUDec64TC{ "00000000000000000000000000000078", 2, RoundDown, 0, ErrInfinity },
+        BSONToUDec64TC{ "000000000000000000000000000000f8", 2, RoundDown, 0, ErrInfinity },
+        BSONToUDec64TC{ "0000000000000000000000000000007c", 2, RoundDown, 0, ErrNaN },
+        BSONToUDec64TC{ "0000000000000000000000000000007e", 2, RoundDown, 0, ErrNaN },
+        // bad length
+        BSONToUDec64TC{ "000000000000000000000000000040", 2, RoundDown,
+                0, strconv.ErrSyntax },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := BSONDecimal128ToUDec64(b, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: bsontoudec64(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+func TestDecimal128IsNaNInf(t *testing.T) {
+    hi, _ := UDec64(12345).ToDecimal128BID(2)
+    if Decimal128IsNaN(hi) || Decimal128IsInf(hi) {
+        t.Errorf("Result mismatch: isnaninf(%x)", hi)
+    }
+    if !Decimal128IsNaN(0x7c00000000000000) || Decimal128IsInf(0x7c00000000000000) {
+        t.Errorf("Result mismatch: isnan(7c00000000000000)")
+    }
+    if !Decimal128IsInf(0xf800000000000000) || Decimal128IsNaN(0xf800000000000000) {
+        t.Errorf("Result mismatch: isinf(f800000000000000)")
+    }
+}
@@ -0,0 +1,210 @@
+/*
+ * decimal64.go - IEEE 754-2008 decimal64 encoding (BID and DPD)
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "strconv"
+)
+
+var (
+    // value is NaN
+    ErrNaN = errors.New("godec64: value is NaN")
+    // value is infinity
+    ErrInfinity = errors.New("godec64: value is infinity")
+)
+
+const (
+    decimal64Bias = 398
+    decimal64EMin = -398 // minimal exponent of coefficient
+    decimal64EMax = 369 // maximal exponent of coefficient
+    decimal64MaxCoef = 9999999999999999
+)
+
+// DPD encoding of three digits and decoding of declets
+var dpdEncodeTable [1000]uint16
+var dpdDecodeTable [1024]uint16
+
+// encode three digits to declet
+func encodeDeclet(v uint) uint16 {
+    d2, d1, d0 := v/100, (v/10)%10, v%10
+    // bits of digits: a b c d, e f g h, i j k m
+    a, e, i := d2>>3, d1>>3, d0>>3
+    bcd, fgh, jkm := d2&7, d1&7, d0&7
+    d, h, m := d2&1, d1&1, d0&1
+    var r uint
+    switch a<<2 | e<<1 | i {
+    case 0: // 000
+        r = bcd<<7 | fgh<<4 | jkm
+    case 1: // 001
+        r = bcd<<7 | fgh<<4 | 8 | m
+    case 2: // 010
+        r = bcd<<7 | (jkm&6)<<4 | h<<4 | 10 | m
+    case 4: // 100
+        r = (jkm&6)<<7 | d<<7 | fgh<<4 | 12 | m
+    case 6: // 110
+        r = (jkm&6)<<7 | d<<7 | h<<4 | 14 | m
+    case 5: // 101
+        r = (fgh&6)<<7 | d<<7 | 2<<4 | h<<4 | 14 | m
+    case 3: // 011
+        r = bcd<<7 | 4<<4 | h<<4 | 14 | m
+    case 7: // 111
+        r = d<<7 | 6<<4 | h<<4 | 14 | m
+    }
+    return uint16(r)
+}
+
+func init() {
+    for d:=uint(0); d < 1000; d++ {
+        dpdEncodeTable[d] = encodeDeclet(d)
+    }
+    // non-canonical declets (x x 1 1 x 1 1 1 1 x) decode as if first two bits are 0
+    for i := range dpdDecodeTable {
+        dpdDecodeTable[i] = 0xffff
+    }
+    for d:=uint(0); d < 1000; d++ {
+        dpdDecodeTable[dpdEncodeTable[d]] = uint16(d)
+    }
+    for i := range dpdDecodeTable {
+        if dpdDecodeTable[i]==0xffff {
+            dpdDecodeTable[i] = dpdDecodeTable[i&^0x300]
+        }
+    }
+}
+
+// returns true if decimal64 value (BID or DPD) is NaN
+func Decimal64IsNaN(v uint64) bool {
+    return (v>>58)&0x1f==0x1f
+}
+
+// returns true if decimal64 value (BID or DPD) is infinity
+func Decimal64IsInf(v uint64) bool {
+    return (v>>58)&0x1f==0x1e
+}
+
+// convert UDec64 to decimal64 coefficient and exponent. Coefficient will be rounded
+// if it has more than 16 digits, exponent is clamped to decimal64 range
+func toDecimal64Coef(c uint64, exp int, mode RoundingMode) (uint64, int) {
+    // round coefficient to 16 digits
+    if c > decimal64MaxCoef {
+        k := uint(1)
+        for ; c/uint64_powers[k] > decimal64MaxCoef; k++ { }
+        c = divPow10(c, k, mode)
+        exp += int(k)
+        if c > decimal64MaxCoef {
+            // rounded to 10^16
+            c /= 10
+            exp++
+        }
+    }
+    // clamp exponent
+    if exp < decimal64EMin {
+        c = divPow10(c, uint(decimal64EMin-exp), mode)
+        exp = decimal64EMin
+    }
+    for ; exp > decimal64EMax && c!=0 && c*10 <= decimal64MaxCoef; exp-- {
+        c *= 10 // fold-down
+    }
+    if c==0 && exp > decimal64EMax {
+        exp = decimal64EMax
+    }
+    return c, exp
+}
+
+// convert to decimal64 in BID (Binary Integer Decimal) encoding
+func (a UDec64) ToDecimal64BID(precision uint, mode RoundingMode) uint64 {
+    c, exp := toDecimal64Coef(uint64(a), -int(precision), mode)
+    bexp := uint64(exp+decimal64Bias)
+    if c < 1<<53 {
+        return bexp<<53 | c
+    }
+    return 3<<61 | bexp<<51 | (c & (1<<51-1))
+}
+
+// convert decimal64 in BID (Binary Integer Decimal) encoding to UDec64
+// with precision and rounding mode
+func Decimal64BIDToUDec64(v uint64, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if Decimal64IsNaN(v) { return 0, ErrNaN }
+    if Decimal64IsInf(v) { return 0, ErrInfinity }
+    var c uint64
+    var bexp int
+    if (v>>61)&3!=3 {
+        bexp = int((v>>53)&0x3ff)
+        c = v & (1<<53-1)
+    } else {
+        bexp = int((v>>51)&0x3ff)
+        c = 1<<53 | (v & (1<<51-1))
+    }
+    if c > decimal64MaxCoef {
+        c = 0 // non-canonical
+    }
+    if c!=0 && v>>63!=0 {
+        return 0, strconv.ErrRange // negative
+    }
+    return coefToUDec64(c, bexp-decimal64Bias, precision, mode)
+}
+
+// convert to decimal64 in DPD (Densely Packed Decimal) encoding
+func (a UDec64) ToDecimal64DPD(precision uint, mode RoundingMode) uint64 {
+    c, exp := toDecimal64Coef(uint64(a), -int(precision), mode)
+    bexp := uint64(exp+decimal64Bias)
+    var cont uint64
+    for i:=uint(0); i < 5; i++ {
+        cont |= uint64(dpdEncodeTable[c%1000]) << (10*i)
+        c /= 1000
+    }
+    // c is leading digit
+    var comb uint64
+    if c < 8 {
+        comb = (bexp>>8)<<3 | c
+    } else {
+        comb = 3<<3 | (bexp>>8)<<1 | (c&1)
+    }
+    return comb<<58 | (bexp&0xff)<<50 | cont
+}
+
+// convert decimal64 in DPD (Densely Packed Decimal) encoding to UDec64
+// with precision and rounding mode
+func Decimal64DPDToUDec64(v uint64, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if Decimal64IsNaN(v) { return 0, ErrNaN }
+    if Decimal64IsInf(v) { return 0, ErrInfinity }
+    comb := (v>>58)&0x1f
+    var c, bexp uint64
+    if comb>>3!=3 {
+        bexp = comb>>3
+        c = comb&7
+    } else {
+        bexp = (comb>>1)&3
+        c = 8 + (comb&1)
+    }
+    bexp = bexp<<8 | (v>>50)&0xff
+    for i:=4; i >= 0; i-- {
+        c = c*1000 + uint64(dpdDecodeTable[(v>>(10*uint(i)))&0x3ff])
+    }
+    if c!=0 && v>>63!=0 {
+        return 0, strconv.ErrRange // negative
+    }
+    return coefToUDec64(c, int(bexp)-decimal64Bias, precision, mode)
+}
@@ -0,0 +1,197 @@
+/*
+ * decimal64_test.go - IEEE 754-2008 decimal64 encoding tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "strconv"
+    "testing"
+)
+
+type UDec64ToDecimal64TC struct {
+    value UDec64
+    precision uint
+    mode RoundingMode
+    expBID uint64
+    expDPD uint64
+}
+
+func TestUDec64ToDecimal64(t *testing.T) {
+    testCases := []UDec64ToDecimal64TC {
+        UDec64ToDecimal64TC{ 0, 0, RoundDown, 0x31c0000000000000, 0x2238000000000000 },
+        UDec64ToDecimal64TC{ 1, 0, RoundDown, 0x31c0000000000001, 0x2238000000000001 },
+        UDec64ToDecimal64TC{ 750, 2, RoundDown, 0x31800000000002ee, 0x22300000000003d0 },
+        UDec64ToDecimal64TC{ 9999999999999999, 0, RoundDown,
+                0x6c7386f26fc0ffff, 0x6e38ff3fcff3fcff },
+        UDec64ToDecimal64TC{ 1234567890123456, 0, RoundDown,
+                0x31c462d53c8abac0, 0x263934b9c1e28e56 },
+        // rounding to 16 digits
+        UDec64ToDecimal64TC{ 12345678901234567, 1, RoundDown,
+                0x31c462d53c8abac0, 0x263934b9c1e28e56 },
+        UDec64ToDecimal64TC{ 12345678901234567, 1, RoundHalfUp,
+                0x31c462d53c8abac1, 0x263934b9c1e28e57 },
+        UDec64ToDecimal64TC{ 99999999999999999, 1, RoundHalfUp,
+                0x31e38d7ea4c68000, 0x263c000000000000 },
+    }
+    for i, tc := range testCases {
+        result := tc.value.ToDecimal64BID(tc.precision, tc.mode)
+        if tc.expBID!=result {
+            t.Errorf("Result mismatch: %d: todecimal64bid(%v,%v,%v)->%x!=%x",
+                     i, tc.value, tc.precision, tc.mode, tc.expBID, result)
+        }
+        result = tc.value.ToDecimal64DPD(tc.precision, tc.mode)
+        if tc.expDPD!=result {
+            t.Errorf("Result mismatch: %d: todecimal64dpd(%v,%v,%v)->%x!=%x",
+                     i, tc.value, tc.precision, tc.mode, tc.expDPD, result)
+        }
+    }
+}
+
+type Decimal64ToUDec64TC struct {
+    value uint64
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestDecimal64BIDToUDec64(t *testing.T) {
+    testCases := []Decimal64ToUDec64TC {
+        Decimal64ToUDec64TC{ 0x31c0000000000000, 2, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0xb1c0000000000000, 2, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0x31c0000000000001, 2, RoundDown, 100, nil },
+        Decimal64ToUDec64TC{ 0x31800000000002ee, 2, RoundDown, 750, nil },
+        Decimal64ToUDec64TC{ 0x31800000000002ee, 1, RoundDown, 75, nil },
+        Decimal64ToUDec64TC{ 0x31800000000002ee, 0, RoundDown, 7, nil },
+        Decimal64ToUDec64TC{ 0x31800000000002ee, 0, RoundHalfUp, 8, nil },
+        Decimal64ToUDec64TC{ 0x31800000000002ee, 5, RoundDown, 750000, nil },
+        Decimal64ToUDec64TC{ 0x6c7386f26fc0ffff, 3, RoundDown,
+                9999999999999999000, nil },
+        Decimal64ToUDec64TC{ 0x6c7386f26fc0ffff, 4, RoundDown, 0, strconv.ErrRange },
+        // non-canonical coefficient
+        Decimal64ToUDec64TC{ 0x6c7fffffffffffff, 4, RoundDown, 0, nil },
+        // smallest and largest exponents
+        Decimal64ToUDec64TC{ 0x0000000000000001, 18, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0x0000000000000001, 18, RoundUp, 1, nil },
+        Decimal64ToUDec64TC{ 0x5fe0000000000001, 0, RoundDown, 0, strconv.ErrRange },
+        Decimal64ToUDec64TC{ 0x5fe0000000000000, 0, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0xb1c0000000000001, 2, RoundDown, 0, strconv.ErrRange },
+        Decimal64ToUDec64TC{ 0x7800000000000000, 2, RoundDown, 0, ErrInfinity },
+        Decimal64ToUDec64TC{ 0xf800000000000000, 2, RoundDown, 0, ErrInfinity },
+        Decimal64ToUDec64TC{ 0x7c00000000000000, 2, RoundDown, 0, ErrNaN },
+        Decimal64ToUDec64TC{ 0x7e00000000000000, 2, RoundDown, 0, ErrNaN },
+    }
+    for i, tc := range testCases {
+        result, err := Decimal64BIDToUDec64(tc.value, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: decimal64bidtoudec64(%x,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+func TestDecimal64DPDToUDec64(t *testing.T) {
+    testCases := []Decimal64ToUDec64TC {
+        Decimal64ToUDec64TC{ 0x2238000000000000, 2, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0xa238000000000000, 2, RoundDown, 0, nil },
+        Decimal64ToUDec64TC{ 0x2238000000000001, 2, RoundDown, 100, nil },
+        Decimal64ToUDec64TC{ 0x22300000000003d0, 2, RoundDown, 750, nil },
+        Decimal64ToUDec64TC{ 0x22300000000003d0, 0, RoundHalfEven, 8, nil },
+        Decimal64ToUDec64TC{ 0x6e38ff3fcff3fcff, 3, RoundDown,
+                9999999999999999000, nil },
+        Decimal64ToUDec64TC{ 0x6e38ff3fcff3fcff, 4, RoundDown, 0, strconv.ErrRange },
+        // non-canonical declets
+        Decimal64ToUDec64TC{ 0x22380000000003ff, 0, RoundDown, 999, nil },
+        Decimal64ToUDec64TC{ 0x22380000000001ff, 0, RoundDown, 999, nil },
+        Decimal64ToUDec64TC{ 0x2238000000000000|0x2ff, 0, RoundDown, 999, nil },
+        Decimal64ToUDec64TC{ 0xa238000000000001, 2, RoundDown, 0, strconv.ErrRange },
+        Decimal64ToUDec64TC{ 0x7800000000000000, 2, RoundDown, 0, ErrInfinity },
+        Decimal64ToUDec64TC{ 0x7c00000000000000, 2, RoundDown, 0, ErrNaN },
+    }
+    for i, tc := range testCases {
+        result, err := Decimal64DPDToUDec64(tc.value, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: decimal64dpdtoudec64(%x,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+func TestDecimal64Declets(t *testing.T) {
+    for d:=uint(0); d < 1000; d++ {
+        if uint(dpdDecodeTable[dpdEncodeTable[d]])!=d {
+            t.Errorf("Declet mismatch: %d->%x->%d", d, dpdEncodeTable[d],
+                     dpdDecodeTable[dpdEncodeTable[d]])
+        }
+    }
+    for i, d := range dpdDecodeTable {
+        if d > 999 {
+            t.Errorf("Declet decoding mismatch: %x->%d", i, d)
+        }
+    }
+}
+
+func TestDecimal64RoundTrip(t *testing.T) {
+    v := uint64(3)
+    for i:=0; i < 10000; i++ {
+        v = v*6364136223846793005 + 1442695040888963407
+        a := UDec64((v>>11) % 10000000000000000)
+        precision := uint(i)%19
+        bid := a.ToDecimal64BID(precision, RoundDown)
+        result, err := Decimal64BIDToUDec64(bid, precision, RoundDown)
+        if a!=result || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%x->%v,%v",
+                     i, a, precision, bid, result, err)
+        }
+        dpd := a.ToDecimal64DPD(precision, RoundDown)
+        result, err = Decimal64DPDToUDec64(dpd, precision, RoundDown)
+        if a!=result || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%x->%v,%v",
+                     i, a, precision, dpd, result, err)
+        }
+    }
+}
+
+type Decimal64CoefTC struct {
+    coef uint64
+    exp int
+    expCoef uint64
+    expExp int
+}
+
+func TestDecimal64Clamp(t *testing.T) {
+    testCases := []Decimal64CoefTC {
+        Decimal64CoefTC{ 5, 372, 5000, 369 },
+        Decimal64CoefTC{ 0, 400, 0, 369 },
+        Decimal64CoefTC{ 1234, -400, 12, -398 },
+        Decimal64CoefTC{ 1234, -410, 0, -398 },
+        Decimal64CoefTC{ 12345678901234567, -2, 1234567890123457, -1 },
+    }
+    for i, tc := range testCases {
+        coef, exp := toDecimal64Coef(tc.coef, tc.exp, RoundHalfUp)
+        if tc.expCoef!=coef || tc.expExp!=exp {
+            t.Errorf("Result mismatch: %d: clamp(%v,%v)->%v,%v!=%v,%v",
+                     i, tc.coef, tc.exp, tc.expCoef, tc.expExp, coef, exp)
+        }
+    }
+}
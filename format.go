@@ -0,0 +1,293 @@
+/*
+ * format.go - formatting with options
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "strconv"
+    "unicode/utf8"
+)
+
+// grouping (separator 1000) mode
+type GroupingMode uint8
+
+const (
+    // grouping enabled for locale formatting, disabled for plain formatting
+    GroupingAuto GroupingMode = iota
+    // always group digits
+    GroupingOn
+    // never group digits
+    GroupingOff
+)
+
+// sign display mode
+type SignDisplay uint8
+
+const (
+    // display sign only for negative values
+    SignAuto SignDisplay = iota
+    // always display sign ('+' or '-')
+    SignAlways
+    // display sign except for zero
+    SignExceptZero
+    // never display sign
+    SignNever
+)
+
+// zero value style
+type ZeroStyle uint8
+
+const (
+    // zero is formatted as any other value
+    ZeroNumber ZeroStyle = iota
+    // zero is formatted as single zero digit without fraction
+    ZeroInteger
+    // zero is formatted as '-'
+    ZeroDash
+    // zero is formatted as empty string
+    ZeroBlank
+)
+
+// formatting options. Zero value gives default formatting
+type FormatOptions struct {
+    // number of digits after comma to display. Used only if HasDisplayPrecision
+    // is set, otherwise precision of value is used
+    DisplayPrecision uint
+    HasDisplayPrecision bool
+    // trim trailing zeroes in fraction
+    TrimZeroes bool
+    // minimal number of digits after comma (also after trimming zeroes)
+    MinFractionDigits uint
+    // grouping mode
+    Grouping GroupingMode
+    // rounding mode used if display precision is lower than precision
+    Rounding RoundingMode
+    // sign display mode
+    Sign SignDisplay
+    // format value as negative (UDec64 holds only magnitude)
+    Negative bool
+    // minimal display width of output (in terminal columns, East Asian wide
+    // characters take two columns), output is padded at left
+    Width uint
+    // pad by zeroes (after sign) instead of spaces
+    PadZeroes bool
+    // align on comma: fraction part is padded at right by spaces to
+    // display precision (or MinFractionDigits if greater) digits
+    AlignComma bool
+    // zero value style
+    Zero ZeroStyle
+    // CLDR numbering system (for example "thai" or "native") used by locale
+    // formatting instead of numbering system of locale
+    NumberingSystem string
+}
+
+// options for legacy formatting routines. At least one digit after comma
+// is printed unless both precision and display precision are zero (also
+// "123.0" if only display precision is zero)
+func legacyFormatOptions(precision, displayPrecision uint,
+                         trimZeroes bool) FormatOptions {
+    opts := FormatOptions{ DisplayPrecision: displayPrecision,
+                HasDisplayPrecision: true, TrimZeroes: trimZeroes }
+    if precision!=0 || displayPrecision!=0 {
+        opts.MinFractionDigits = 1
+    }
+    return opts
+}
+
+// append number in plain form (digits and '.') to dst. v and precision must be
+// already rounded to display precision
+func appendPlain(dst []byte, v uint64, precision, displayPrecision uint,
+                 opts *FormatOptions) []byte {
+    var buf [24]byte
+    digits := strconv.AppendUint(buf[:0], v, 10)
+    slen := len(digits)
+    prec := int(precision)
+    // integer part
+    if slen > prec {
+        dst = append(dst, digits[:slen-prec]...)
+    } else {
+        dst = append(dst, '0')
+    }
+    // fraction part. digit j of fraction: zero if j<prec-slen or j>=prec
+    fracStart := prec-slen // position of first significant digit in fraction
+    fracEnd := int(displayPrecision)
+    if opts.TrimZeroes {
+        if fracEnd > prec { fracEnd = prec }
+        for ; fracEnd>0 && fracEnd>fracStart; fracEnd-- {
+            if digits[fracEnd-1-fracStart]!='0' { break }
+        }
+        if fracEnd<=fracStart && fracEnd>0 {
+            fracEnd = 0 // only zeroes
+        }
+    }
+    if fracEnd < int(opts.MinFractionDigits) {
+        fracEnd = int(opts.MinFractionDigits)
+    }
+    if fracEnd==0 { return dst }
+    dst = append(dst, '.')
+    for j:=0; j < fracEnd; j++ {
+        if j<fracStart || j>=prec {
+            dst = append(dst, '0')
+        } else {
+            dst = append(dst, digits[j-fracStart])
+        }
+    }
+    return dst
+}
+
+// localize plain number: replace digits, comma and add separators 1000
+func appendLocalized(dst []byte, s []byte, l *LocFmt, grouping bool) []byte {
+    slen := len(s)
+    commaIdx := slen
+    for i, c := range s {
+        if c=='.' {
+            commaIdx = i
+            break
+        }
+    }
+    for k:=0; k < commaIdx; k++ {
+        dst = utf8.AppendRune(dst, l.Digits[s[k]-'0'])
+        r := commaIdx-k-1 // remaining digits
+        if grouping && r>0 {
+            if (!l.Sep100and1000 && r%3==0) ||
+                (l.Sep100and1000 && (r==3 || (r>3 && (r-3)&1==0))) {
+                dst = utf8.AppendRune(dst, l.Sep1000)
+            }
+        }
+    }
+    if commaIdx!=slen {
+        dst = utf8.AppendRune(dst, l.Comma)
+        for k:=commaIdx+1; k < slen; k++ {
+            dst = utf8.AppendRune(dst, l.Digits[s[k]-'0'])
+        }
+    }
+    return dst
+}
+
+// append formatted number to dst
+func (a UDec64) appendFormat(dst []byte, precision uint, opts *FormatOptions,
+                             l *LocFmt, grouping bool) []byte {
+    displayPrecision := precision
+    if opts.HasDisplayPrecision {
+        displayPrecision = opts.DisplayPrecision
+    }
+    v := uint64(a)
+    if displayPrecision < precision {
+        v = divPow10(v, precision-displayPrecision, opts.Rounding)
+        precision = displayPrecision
+    }
+    var bodyBuf [96]byte
+    body := bodyBuf[:0]
+    fracDigits := 0 // number of digits after comma
+    switch {
+    case v==0 && opts.Zero==ZeroInteger:
+        body = utf8.AppendRune(body, l.Digits[0])
+    case v==0 && opts.Zero==ZeroDash:
+        body = append(body, '-')
+    case v==0 && opts.Zero==ZeroBlank:
+    default:
+        var plainBuf [64]byte
+        plain := appendPlain(plainBuf[:0], v, precision, displayPrecision, opts)
+        for i, c := range plain {
+            if c=='.' {
+                fracDigits = len(plain)-i-1
+                break
+            }
+        }
+        body = appendLocalized(body, plain, l, grouping)
+    }
+    // sign
+    var sign byte
+    if opts.Negative && v!=0 {
+        if opts.Sign!=SignNever { sign = '-' }
+    } else if opts.Sign==SignAlways || (opts.Sign==SignExceptZero && v!=0) {
+        sign = '+'
+    }
+    width := BytesWidth(body)
+    if sign!=0 { width++ }
+    // padding at right to align comma
+    alignPad := 0
+    if opts.AlignComma {
+        fracCols := int(displayPrecision)
+        if fracCols < int(opts.MinFractionDigits) {
+            fracCols = int(opts.MinFractionDigits)
+        }
+        if fracCols > fracDigits {
+            alignPad = (fracCols-fracDigits)*RuneWidth(l.Digits[0])
+            if fracDigits==0 {
+                alignPad += RuneWidth(l.Comma)
+            }
+        }
+        width += alignPad
+    }
+    // padding at left
+    padZeroes := opts.PadZeroes && (v!=0 || opts.Zero==ZeroNumber ||
+                    opts.Zero==ZeroInteger)
+    zeroCount := 0
+    if padZeroes && width < int(opts.Width) {
+        zeroWidth := RuneWidth(l.Digits[0])
+        zeroCount = (int(opts.Width)-width) / zeroWidth
+        width += zeroCount*zeroWidth
+    }
+    for ; width < int(opts.Width); width++ {
+        dst = append(dst, ' ')
+    }
+    if sign!=0 {
+        dst = append(dst, sign)
+    }
+    for ; zeroCount > 0; zeroCount-- {
+        dst = utf8.AppendRune(dst, l.Digits[0])
+    }
+    dst = append(dst, body...)
+    for ; alignPad > 0; alignPad-- {
+        dst = append(dst, ' ')
+    }
+    return dst
+}
+
+// format number with options
+func (a UDec64) FormatOpts(precision uint, opts FormatOptions) string {
+    return string(a.appendFormat(nil, precision, &opts, &defaultLocaleFormat,
+                                 opts.Grouping==GroupingOn))
+}
+
+// format number with options to bytes
+func (a UDec64) FormatOptsBytes(precision uint, opts FormatOptions) []byte {
+    return a.appendFormat(nil, precision, &opts, &defaultLocaleFormat,
+                          opts.Grouping==GroupingOn)
+}
+
+// format number with options including locale
+func (a UDec64) LocaleFormatOpts(lang string, precision uint,
+                                 opts FormatOptions) string {
+    return string(a.appendFormat(nil, precision, &opts,
+                    GetLocFmtNumbering(lang, opts.NumberingSystem),
+                    opts.Grouping!=GroupingOff))
+}
+
+// format number with options including locale to bytes
+func (a UDec64) LocaleFormatOptsBytes(lang string, precision uint,
+                                      opts FormatOptions) []byte {
+    return a.appendFormat(nil, precision, &opts,
+                    GetLocFmtNumbering(lang, opts.NumberingSystem),
+                    opts.Grouping!=GroupingOff)
+}
@@ -0,0 +1,134 @@
+/*
+ * format_test.go - formatting with options tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "testing"
+)
+
+type UDec64FmtOptsTC struct {
+    lang string
+    a UDec64
+    precision uint
+    opts FormatOptions
+    expected string
+}
+
+func TestUDec64FormatOpts(t *testing.T) {
+    testCases := []UDec64FmtOptsTC {
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{}, "123456.789" },
+        UDec64FmtOptsTC{ "", 123456700, 3, FormatOptions{ TrimZeroes: true },
+                "123456.7" },
+        UDec64FmtOptsTC{ "", 123456000, 3, FormatOptions{ TrimZeroes: true },
+                "123456" },
+        UDec64FmtOptsTC{ "", 123456000, 3, FormatOptions{ TrimZeroes: true,
+                MinFractionDigits: 2 }, "123456.00" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ TrimZeroes: true,
+                MinFractionDigits: 5 }, "123456.78900" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ DisplayPrecision: 2,
+                HasDisplayPrecision: true }, "123456.78" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ DisplayPrecision: 2,
+                HasDisplayPrecision: true, Rounding: RoundHalfEven }, "123456.79" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ DisplayPrecision: 0,
+                HasDisplayPrecision: true, Rounding: RoundHalfUp }, "123457" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ Grouping: GroupingOn },
+                "123,456.789" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ Grouping: GroupingOff },
+                "123456.789" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ Sign: SignAlways },
+                "+123456.789" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ Negative: true },
+                "-123456.789" },
+        UDec64FmtOptsTC{ "", 123456789, 3, FormatOptions{ Negative: true,
+                Sign: SignNever }, "123456.789" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Sign: SignAlways }, "+0.000" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Sign: SignExceptZero }, "0.000" },
+        UDec64FmtOptsTC{ "", 1, 3, FormatOptions{ Sign: SignExceptZero }, "+0.001" },
+        UDec64FmtOptsTC{ "", 1, 3, FormatOptions{ DisplayPrecision: 2,
+                HasDisplayPrecision: true, Negative: true }, "0.00" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Zero: ZeroInteger }, "0" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Zero: ZeroDash }, "-" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Zero: ZeroBlank }, "" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Zero: ZeroDash, Width: 6 },
+                "     -" },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Zero: ZeroDash, Width: 6,
+                PadZeroes: true }, "     -" },
+        UDec64FmtOptsTC{ "", 12345, 2, FormatOptions{ Width: 10 }, "    123.45" },
+        UDec64FmtOptsTC{ "", 12345, 2, FormatOptions{ Width: 3 }, "123.45" },
+        UDec64FmtOptsTC{ "", 12345, 2, FormatOptions{ Width: 10, Negative: true },
+                "   -123.45" },
+        UDec64FmtOptsTC{ "", 12345, 2, FormatOptions{ Width: 10, Negative: true,
+                PadZeroes: true }, "-000123.45" },
+        // locale
+        UDec64FmtOptsTC{ "de", 123456789, 3, FormatOptions{}, "123.456,789" },
+        UDec64FmtOptsTC{ "de", 123456789, 3, FormatOptions{ Grouping: GroupingOff },
+                "123456,789" },
+        UDec64FmtOptsTC{ "hi", 123456789012, 3, FormatOptions{ Negative: true },
+                "-12,34,56,789.012" },
+        UDec64FmtOptsTC{ "ar", 12345, 2, FormatOptions{ Width: 8, PadZeroes: true },
+                "٠٠١٢٣٫٤٥" },
+        UDec64FmtOptsTC{ "ar", 0, 2, FormatOptions{ Zero: ZeroInteger }, "٠" },
+        UDec64FmtOptsTC{ "bn", 1234567, 2, FormatOptions{ Width: 10 }, " ১২,৩৪৫.৬৭" },
+        UDec64FmtOptsTC{ "ar", 1234567, 2, FormatOptions{ Width: 10 }, " ١٢٬٣٤٥٫٦٧" },
+        UDec64FmtOptsTC{ "my", 1234567, 2, FormatOptions{ Width: 12,
+                PadZeroes: true }, "၀၀၀၁၂,၃၄၅.၆၇" },
+        // align on comma
+        UDec64FmtOptsTC{ "", 12345, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                AlignComma: true }, "    12.345" },
+        UDec64FmtOptsTC{ "", 12300, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                AlignComma: true }, "    12.3  " },
+        UDec64FmtOptsTC{ "", 12000, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                AlignComma: true }, "    12    " },
+        UDec64FmtOptsTC{ "", 0, 3, FormatOptions{ Width: 10, Zero: ZeroDash,
+                AlignComma: true }, "     -    " },
+        UDec64FmtOptsTC{ "", 12000, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                MinFractionDigits: 1, AlignComma: true }, "    12.0  " },
+        UDec64FmtOptsTC{ "ar", 12300, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                AlignComma: true }, "    ١٢٫٣  " },
+        UDec64FmtOptsTC{ "bn", 1230000, 3, FormatOptions{ Width: 10, TrimZeroes: true,
+                AlignComma: true }, " ১,২৩০    " },
+    }
+    for i, tc := range testCases {
+        a := tc.a
+        var result string
+        var resultBytes []byte
+        if tc.lang=="" {
+            result = tc.a.FormatOpts(tc.precision, tc.opts)
+            resultBytes = tc.a.FormatOptsBytes(tc.precision, tc.opts)
+        } else {
+            result = tc.a.LocaleFormatOpts(tc.lang, tc.precision, tc.opts)
+            resultBytes = tc.a.LocaleFormatOptsBytes(tc.lang, tc.precision, tc.opts)
+        }
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.opts, tc.expected, result)
+        }
+        if tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytes(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.opts, tc.expected,
+                     string(resultBytes))
+        }
+        if tc.a!=a {
+            t.Errorf("Argument has been modified: %d: %v!=%v", i, a, tc.a)
+        }
+    }
+}
@@ -0,0 +1,107 @@
+/*
+ * formatter.go - precompiled locale formatter
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+// locale formatter with locale formatting info and options resolved once.
+// Later registration of locales does not change existing formatter.
+// Formatter is immutable and can be used concurrently
+type LocaleFormatter struct {
+    l LocFmt
+    opts FormatOptions
+    grouping bool
+}
+
+// create locale formatter for language tag and formatting options.
+// Returns ErrLanguageTag if tag is invalid or numbering system is unknown
+func NewLocaleFormatter(tag string, opts FormatOptions) (*LocaleFormatter, error) {
+    lt, err := ParseLanguageTag(tag)
+    if err!=nil { return nil, err }
+    for _, ns := range []string{ lt.NumberingSystem, opts.NumberingSystem } {
+        if _, ok := numberingSystems[ns]; ns!="" && ns!="native" && !ok {
+            return nil, ErrLanguageTag
+        }
+    }
+    return &LocaleFormatter{ findLocFmt(tag, opts.NumberingSystem), opts,
+                opts.Grouping!=GroupingOff }, nil
+}
+
+// create locale formatter for locale formatting info and formatting options.
+// Returns ErrLocFmt if locale formatting info is invalid
+func NewLocaleFormatterLocFmt(l LocFmt, opts FormatOptions) (*LocaleFormatter, error) {
+    if err := checkLocFmt(&l); err!=nil { return nil, err }
+    l.Digits = append([]rune(nil), l.Digits...)
+    return &LocaleFormatter{ l, opts, opts.Grouping!=GroupingOff }, nil
+}
+
+// get locale formatting info of formatter
+func (f *LocaleFormatter) LocFmt() LocFmt {
+    l := f.l
+    l.Digits = append([]rune(nil), f.l.Digits...)
+    return l
+}
+
+// get formatting options of formatter
+func (f *LocaleFormatter) Options() FormatOptions {
+    return f.opts
+}
+
+// append formatted number to dst
+func (f *LocaleFormatter) Append(dst []byte, a UDec64, precision uint) []byte {
+    return a.appendFormat(dst, precision, &f.opts, &f.l, f.grouping)
+}
+
+// append formatted number with sign to dst (UDec64 holds only magnitude)
+func (f *LocaleFormatter) AppendSigned(dst []byte, a UDec64, precision uint,
+                                      negative bool) []byte {
+    opts := f.opts
+    opts.Negative = negative
+    return a.appendFormat(dst, precision, &opts, &f.l, f.grouping)
+}
+
+// format number
+func (f *LocaleFormatter) Format(a UDec64, precision uint) string {
+    var buf [128]byte
+    return string(f.Append(buf[:0], a, precision))
+}
+
+// format number with sign (UDec64 holds only magnitude)
+func (f *LocaleFormatter) FormatSigned(a UDec64, precision uint,
+                                      negative bool) string {
+    var buf [128]byte
+    return string(f.AppendSigned(buf[:0], a, precision, negative))
+}
+
+// parse number formatted in locale of formatter. Parsing does not allocate
+// if number has at most 64 digits and comma (separators are not counted),
+// longer numbers are copied to buffer allocated on heap
+func (f *LocaleFormatter) Parse(str string, precision uint,
+                                rounding bool) (UDec64, error) {
+    return localeParseUDec64(&f.l, str, precision, rounding)
+}
+
+// parse number formatted in locale of formatter from bytes (allocation is same
+// as in Parse)
+func (f *LocaleFormatter) ParseBytes(str []byte, precision uint,
+                                     rounding bool) (UDec64, error) {
+    return localeParseUDec64Bytes(&f.l, str, precision, rounding)
+}
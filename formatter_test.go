@@ -0,0 +1,180 @@
+/*
+ * formatter_test.go - precompiled locale formatter tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "strconv"
+    "strings"
+    "testing"
+)
+
+type LocaleFormatterTC struct {
+    lang string
+    opts FormatOptions
+    a UDec64
+    precision uint
+    negative bool
+    expected string
+}
+
+func TestLocaleFormatter(t *testing.T) {
+    testCases := []LocaleFormatterTC {
+        LocaleFormatterTC{ "en", FormatOptions{}, 123456789, 2, false,
+                "1,234,567.89" },
+        LocaleFormatterTC{ "de-CH", FormatOptions{}, 123456789, 2, true,
+                "-1’234’567.89" },
+        LocaleFormatterTC{ "pl_PL.UTF-8", FormatOptions{}, 123456789, 2, false,
+                "1 234 567,89" },
+        LocaleFormatterTC{ "en-IN", FormatOptions{ DisplayPrecision: 1,
+                HasDisplayPrecision: true, Rounding: RoundHalfUp }, 123456789, 2, false,
+                "12,34,567.9" },
+        LocaleFormatterTC{ "th", FormatOptions{ NumberingSystem: "native" },
+                123456789, 2, false, "๑,๒๓๔,๕๖๗.๘๙" },
+        LocaleFormatterTC{ "ar", FormatOptions{ Grouping: GroupingOff },
+                123456789, 2, false, "١٢٣٤٥٦٧٫٨٩" },
+        LocaleFormatterTC{ "fr", FormatOptions{ Width: 12, TrimZeroes: true },
+                123456700, 2, false, "   1 234 567" },
+    }
+    for i, tc := range testCases {
+        f, err := NewLocaleFormatter(tc.lang, tc.opts)
+        if err!=nil {
+            t.Errorf("Result mismatch: %d: new(%v)->%v", i, tc.lang, err)
+            continue
+        }
+        result := f.FormatSigned(tc.a, tc.precision, tc.negative)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.expected, result)
+        }
+        // same as locale formatting
+        opts := tc.opts
+        opts.Negative = tc.negative
+        expected := tc.a.LocaleFormatOpts(tc.lang, tc.precision, opts)
+        if expected!=result {
+            t.Errorf("Result mismatch: %d: fmtOpts(%v,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, expected, result)
+        }
+        resultBytes := f.AppendSigned([]byte("x"), tc.a, tc.precision, tc.negative)
+        if "x"+tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: append(%v,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, "x"+tc.expected,
+                     string(resultBytes))
+        }
+        if tc.negative { continue }
+        if result := f.Format(tc.a, tc.precision); tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.expected, result)
+        }
+        if tc.opts.HasDisplayPrecision || tc.opts.Width!=0 { continue }
+        if v, err := f.Parse(tc.expected, tc.precision, false); v!=tc.a || err!=nil {
+            t.Errorf("Result mismatch: %d: parse(%v,%s,%v)->%v,%v!=%v,%v",
+                     i, tc.expected, tc.lang, tc.precision, tc.a, nil, v, err)
+        }
+        v, err := f.ParseBytes([]byte(tc.expected), tc.precision, false)
+        if v!=tc.a || err!=nil {
+            t.Errorf("Result mismatch: %d: parseBytes(%v,%s,%v)->%v,%v!=%v,%v",
+                     i, tc.expected, tc.lang, tc.precision, tc.a, nil, v, err)
+        }
+    }
+}
+
+func TestNewLocaleFormatterErrors(t *testing.T) {
+    if _, err := NewLocaleFormatter("en US", FormatOptions{}); err!=ErrLanguageTag {
+        t.Errorf("Result mismatch: new(en US)->%v!=%v", ErrLanguageTag, err)
+    }
+    if _, err := NewLocaleFormatter("en-u-nu-xxxx", FormatOptions{});
+            err!=ErrLanguageTag {
+        t.Errorf("Result mismatch: new(en-u-nu-xxxx)->%v!=%v", ErrLanguageTag, err)
+    }
+    if _, err := NewLocaleFormatter("en", FormatOptions{ NumberingSystem: "xxxx" });
+            err!=ErrLanguageTag {
+        t.Errorf("Result mismatch: new(en,xxxx)->%v!=%v", ErrLanguageTag, err)
+    }
+    if _, err := NewLocaleFormatterLocFmt(LocFmt{ '.', ',', ',', false,
+                []rune("0123") }, FormatOptions{}); err!=ErrLocFmt {
+        t.Errorf("Result mismatch: newLocFmt->%v!=%v", ErrLocFmt, err)
+    }
+    f, err := NewLocaleFormatterLocFmt(LocFmt{ '.', '\'', '\'', false,
+                []rune("0123456789") }, FormatOptions{})
+    if err!=nil {
+        t.Fatalf("newLocFmt: %v", err)
+    }
+    if result := f.Format(123456789, 2); result!="1'234'567.89" {
+        t.Errorf("Result mismatch: fmt->%v!=%v", "1'234'567.89", result)
+    }
+    if _, err := f.Parse("1'234'x67.89", 2, false); err!=strconv.ErrSyntax {
+        t.Errorf("Result mismatch: parse->%v!=%v", strconv.ErrSyntax, err)
+    }
+}
+
+func TestLocaleFormatterAllocs(t *testing.T) {
+    f, err := NewLocaleFormatter("hi-IN-u-nu-deva", FormatOptions{})
+    if err!=nil {
+        t.Fatalf("new: %v", err)
+    }
+    buf := make([]byte, 0, 128)
+    str := f.Format(123456789, 2)
+    strBytes := []byte(str)
+    var v UDec64
+    if allocs := testing.AllocsPerRun(100, func() {
+        buf = f.AppendSigned(buf[:0], 123456789, 2, true)
+    }); allocs!=0 {
+        t.Errorf("Result mismatch: append allocs: %v", allocs)
+    }
+    if allocs := testing.AllocsPerRun(100, func() {
+        str = f.Format(123456789, 2)
+    }); allocs>1 {
+        t.Errorf("Result mismatch: format allocs: %v", allocs)
+    }
+    if allocs := testing.AllocsPerRun(100, func() {
+        v, err = f.Parse(str, 2, false)
+    }); allocs!=0 {
+        t.Errorf("Result mismatch: parse allocs: %v", allocs)
+    }
+    if allocs := testing.AllocsPerRun(100, func() {
+        v, err = f.ParseBytes(strBytes, 2, false)
+    }); allocs!=0 {
+        t.Errorf("Result mismatch: parseBytes allocs: %v", allocs)
+    }
+    if v!=123456789 || err!=nil {
+        t.Errorf("Result mismatch: parse(%v)->%v,%v", str, v, err)
+    }
+    // 64 digits and comma fit in parse buffer, 65 do not
+    str = "1,234.5" + strings.Repeat("0", 58)
+    if allocs := testing.AllocsPerRun(100, func() {
+        v, err = f.Parse(str, 2, false)
+    }); allocs!=0 {
+        t.Errorf("Result mismatch: parse 64 allocs: %v", allocs)
+    }
+    if v!=123450 || err!=nil {
+        t.Errorf("Result mismatch: parse(%v)->%v,%v", str, v, err)
+    }
+    str += "0"
+    if allocs := testing.AllocsPerRun(100, func() {
+        v, err = f.Parse(str, 2, false)
+    }); allocs>1 {
+        t.Errorf("Result mismatch: parse 65 allocs: %v", allocs)
+    }
+    if v!=123450 || err!=nil {
+        t.Errorf("Result mismatch: parse(%v)->%v,%v", str, v, err)
+    }
+}
@@ -0,0 +1,308 @@
+//go:build ignore
+
+/*
+ * gen_locales.go - generate locale table from CLDR snapshot
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Generator of locale_table.go from CLDR JSON snapshot in cldr directory
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+)
+
+type cldrNumbers struct {
+    Main map[string]struct {
+        Numbers map[string]json.RawMessage `json:"numbers"`
+    } `json:"main"`
+}
+
+type cldrSymbols struct {
+    Decimal string `json:"decimal"`
+    Group string `json:"group"`
+}
+
+type cldrOtherNumberingSystems struct {
+    Native string `json:"native"`
+}
+
+type cldrDecimalFormats struct {
+    Standard string `json:"standard"`
+}
+
+type cldrCurrencies struct {
+    Main map[string]struct {
+        Numbers struct {
+            Currencies map[string]struct {
+                Symbol string `json:"symbol"`
+            } `json:"currencies"`
+        } `json:"numbers"`
+    } `json:"main"`
+}
+
+type cldrParents struct {
+    Supplemental struct {
+        ParentLocales struct {
+            ParentLocale map[string]string `json:"parentLocale"`
+        } `json:"parentLocales"`
+    } `json:"supplemental"`
+}
+
+type cldrAliases struct {
+    Supplemental struct {
+        Metadata struct {
+            Alias struct {
+                LanguageAlias map[string]struct {
+                    Replacement string `json:"_replacement"`
+                } `json:"languageAlias"`
+            } `json:"alias"`
+        } `json:"metadata"`
+    } `json:"supplemental"`
+}
+
+type cldrNumberingSystems struct {
+    Supplemental struct {
+        NumberingSystems map[string]struct {
+            Digits string `json:"_digits"`
+            Type string `json:"_type"`
+        } `json:"numberingSystems"`
+    } `json:"supplemental"`
+}
+
+func readJSON(name string, v interface{}) {
+    data, err := os.ReadFile(filepath.Join("cldr", name))
+    if err!=nil { log.Fatal(err) }
+    if err := json.Unmarshal(data, v); err!=nil {
+        log.Fatalf("%s: %v", name, err)
+    }
+}
+
+func singleRune(s, what, locale string) rune {
+    r, size := utf8.DecodeRuneInString(s)
+    if size==0 || size!=len(s) {
+        log.Fatalf("%s: %s symbol %q is not single character", locale, what, s)
+    }
+    return r
+}
+
+// alternative group separator accepted while parsing
+func altGroup(r rune) rune {
+    switch r {
+    case ' ', ' ':
+        return ' '
+    case '’':
+        return '\''
+    }
+    return r
+}
+
+// returns true if pattern uses Indian grouping (secondary grouping size 2)
+func indianGrouping(pattern string) bool {
+    intPart := pattern
+    if i := strings.IndexByte(intPart, '.'); i >= 0 {
+        intPart = intPart[:i]
+    }
+    parts := strings.Split(intPart, ",")
+    return len(parts) >= 3 && len(parts[len(parts)-2])==2
+}
+
+func sortedKeys(m map[string]string) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// LocFmt literal for locale and numbering system. Decimal format is taken from
+// default numbering system if it is not defined for numbering system
+func locFmtEntry(locale, ns, defaultNs string, nums map[string]json.RawMessage,
+                 systems map[string]string) string {
+    var syms cldrSymbols
+    var formats cldrDecimalFormats
+    if err := json.Unmarshal(nums["symbols-numberSystem-"+ns], &syms); err!=nil {
+        log.Fatalf("%s: %v", locale, err)
+    }
+    rawFormats, ok := nums["decimalFormats-numberSystem-"+ns]
+    if !ok {
+        rawFormats = nums["decimalFormats-numberSystem-"+defaultNs]
+    }
+    if err := json.Unmarshal(rawFormats, &formats); err!=nil {
+        log.Fatalf("%s: %v", locale, err)
+    }
+    if _, ok := systems[ns]; !ok {
+        log.Fatalf("%s: bad numbering system %s", locale, ns)
+    }
+    comma := singleRune(syms.Decimal, "decimal", locale)
+    group := singleRune(syms.Group, "group", locale)
+    return fmt.Sprintf("LocFmt{ %s, %s, %s, %v, %sDigits }",
+                strconv.QuoteRune(comma), strconv.QuoteRune(group),
+                strconv.QuoteRune(altGroup(group)),
+                indianGrouping(formats.Standard), ns)
+}
+
+func main() {
+    var numbers cldrNumbers
+    var parents cldrParents
+    var aliases cldrAliases
+    var numSystems cldrNumberingSystems
+    var currencies cldrCurrencies
+    readJSON("numbers.json", &numbers)
+    readJSON("parentLocales.json", &parents)
+    readJSON("languageAliases.json", &aliases)
+    readJSON("numberingSystems.json", &numSystems)
+    readJSON("currencies.json", &currencies)
+
+    var out bytes.Buffer
+    out.WriteString("// Code generated by gen_locales.go from CLDR snapshot; DO NOT EDIT.\n\n")
+    out.WriteString("package godec64\n\n")
+
+    systems := make(map[string]string)
+    for ns, system := range numSystems.Supplemental.NumberingSystems {
+        if system.Type!="numeric" { continue }
+        if utf8.RuneCountInString(system.Digits)!=10 {
+            log.Fatalf("bad digits of numbering system %s", ns)
+        }
+        systems[ns] = system.Digits
+    }
+    locales := make([]string, 0, len(numbers.Main))
+    for k := range numbers.Main {
+        locales = append(locales, k)
+    }
+    sort.Strings(locales)
+    var table, nuTable, nativeTable, currencyTable bytes.Buffer
+    for _, locale := range locales {
+        nums := numbers.Main[locale].Numbers
+        var defaultNs string
+        if err := json.Unmarshal(nums["defaultNumberingSystem"], &defaultNs); err!=nil {
+            log.Fatalf("%s: %v", locale, err)
+        }
+        fmt.Fprintf(&table, "    %q: %s,\n", locale,
+                    locFmtEntry(locale, defaultNs, defaultNs, nums, systems))
+        var currencyFormats cldrDecimalFormats
+        if err := json.Unmarshal(nums["currencyFormats-numberSystem-"+defaultNs],
+                                 &currencyFormats); err!=nil {
+            log.Fatalf("%s: %v", locale, err)
+        }
+        fmt.Fprintf(&currencyTable, "    %q: %q,\n", locale, currencyFormats.Standard)
+        if raw, ok := nums["otherNumberingSystems"]; ok {
+            var others cldrOtherNumberingSystems
+            if err := json.Unmarshal(raw, &others); err!=nil {
+                log.Fatalf("%s: %v", locale, err)
+            }
+            if _, ok := systems[others.Native]; others.Native!="" && !ok {
+                log.Fatalf("%s: bad native numbering system %s", locale, others.Native)
+            }
+            if others.Native!="" && others.Native!=defaultNs {
+                fmt.Fprintf(&nativeTable, "    %q: %q,\n", locale, others.Native)
+            }
+        }
+        // symbols for other numbering systems (-u-nu- extension)
+        nsList := make([]string, 0)
+        for k := range nums {
+            if ns := strings.TrimPrefix(k, "symbols-numberSystem-");
+                    ns!=k && ns!=defaultNs {
+                nsList = append(nsList, ns)
+            }
+        }
+        sort.Strings(nsList)
+        for _, ns := range nsList {
+            fmt.Fprintf(&nuTable, "    %q: %s,\n", locale+"-u-nu-"+ns,
+                        locFmtEntry(locale, ns, defaultNs, nums, systems))
+        }
+    }
+
+    out.WriteString("// digits of numbering systems\n")
+    for _, ns := range sortedKeys(systems) {
+        fmt.Fprintf(&out, "var %sDigits []rune = []rune(%q)\n", ns, systems[ns])
+    }
+    out.WriteString("\n// numeric numbering systems by CLDR identifier\n")
+    out.WriteString("var numberingSystems map[string][]rune = map[string][]rune {\n")
+    for _, ns := range sortedKeys(systems) {
+        fmt.Fprintf(&out, "    %q: %sDigits,\n", ns, ns)
+    }
+    out.WriteString("}\n")
+    out.WriteString("\n// locale formatting info by CLDR locale identifier\n")
+    out.WriteString("var localeFormats map[string]LocFmt = map[string]LocFmt {\n")
+    out.Write(table.Bytes())
+    out.WriteString("}\n")
+    out.WriteString("\n// locale formatting info for non-default numbering systems\n")
+    out.WriteString("var localeNumberingFormats map[string]LocFmt = map[string]LocFmt {\n")
+    out.Write(nuTable.Bytes())
+    out.WriteString("}\n")
+    out.WriteString("\n// native numbering systems (-u-nu-native) other than default\n")
+    out.WriteString("var localeNativeNumbering map[string]string = map[string]string {\n")
+    out.Write(nativeTable.Bytes())
+    out.WriteString("}\n")
+
+    out.WriteString("\n// currency format patterns by CLDR locale identifier\n")
+    out.WriteString("var localeCurrencyPatterns map[string]string = map[string]string {\n")
+    out.Write(currencyTable.Bytes())
+    out.WriteString("}\n")
+
+    symbols := make(map[string]string)
+    for locale, data := range currencies.Main {
+        if _, ok := numbers.Main[locale]; !ok {
+            log.Fatalf("%s: currencies for unknown locale", locale)
+        }
+        for code, c := range data.Numbers.Currencies {
+            symbols[locale+":"+code] = c.Symbol
+        }
+    }
+    out.WriteString("\n// locale specific currency symbols by locale and currency code\n")
+    out.WriteString("var localeCurrencySymbols map[string]string = map[string]string {\n")
+    for _, k := range sortedKeys(symbols) {
+        fmt.Fprintf(&out, "    %q: %q,\n", k, symbols[k])
+    }
+    out.WriteString("}\n")
+
+    out.WriteString("\n// CLDR parent locales (other than truncation of last subtag)\n")
+    out.WriteString("var localeParents map[string]string = map[string]string {\n")
+    parentMap := parents.Supplemental.ParentLocales.ParentLocale
+    for _, k := range sortedKeys(parentMap) {
+        fmt.Fprintf(&out, "    %q: %q,\n", k, parentMap[k])
+    }
+    out.WriteString("}\n")
+
+    aliasMap := make(map[string]string)
+    for k, v := range aliases.Supplemental.Metadata.Alias.LanguageAlias {
+        aliasMap[k] = v.Replacement
+    }
+    out.WriteString("\n// deprecated language codes\n")
+    out.WriteString("var localeAliases map[string]string = map[string]string {\n")
+    for _, k := range sortedKeys(aliasMap) {
+        fmt.Fprintf(&out, "    %q: %q,\n", k, aliasMap[k])
+    }
+    out.WriteString("}\n")
+
+    if err := os.WriteFile("locale_table.go", out.Bytes(), 0644); err!=nil {
+        log.Fatal(err)
+    }
+}
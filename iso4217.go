@@ -0,0 +1,209 @@
+/*
+ * iso4217.go - ISO 4217 currency table
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+// ISO 4217 currency
+type Currency struct {
+    // alphabetic code, for example "EUR"
+    Code string
+    // numeric code, for example 978
+    Numeric uint16
+    // number of digits after comma (minor unit)
+    MinorUnits uint
+    // default symbol (standard, not narrow symbol from CLDR English locale),
+    // code if English locale has not own symbol. Locale can use own symbol
+    Symbol string
+}
+
+// active ISO 4217 currencies with minor unit (without precious metals,
+// special drawing rights and testing codes)
+var iso4217Currencies map[string]Currency = map[string]Currency {
+    "AED": Currency{ "AED", 784, 2, "AED" },
+    "AFN": Currency{ "AFN", 971, 2, "AFN" },
+    "ALL": Currency{ "ALL", 8, 2, "ALL" },
+    "AMD": Currency{ "AMD", 51, 2, "AMD" },
+    "ANG": Currency{ "ANG", 532, 2, "ANG" },
+    "AOA": Currency{ "AOA", 973, 2, "AOA" },
+    "ARS": Currency{ "ARS", 32, 2, "ARS" },
+    "AUD": Currency{ "AUD", 36, 2, "A$" },
+    "AWG": Currency{ "AWG", 533, 2, "AWG" },
+    "AZN": Currency{ "AZN", 944, 2, "AZN" },
+    "BAM": Currency{ "BAM", 977, 2, "BAM" },
+    "BBD": Currency{ "BBD", 52, 2, "BBD" },
+    "BDT": Currency{ "BDT", 50, 2, "BDT" },
+    "BGN": Currency{ "BGN", 975, 2, "BGN" },
+    "BHD": Currency{ "BHD", 48, 3, "BHD" },
+    "BIF": Currency{ "BIF", 108, 0, "BIF" },
+    "BMD": Currency{ "BMD", 60, 2, "BMD" },
+    "BND": Currency{ "BND", 96, 2, "BND" },
+    "BOB": Currency{ "BOB", 68, 2, "BOB" },
+    "BOV": Currency{ "BOV", 984, 2, "BOV" },
+    "BRL": Currency{ "BRL", 986, 2, "R$" },
+    "BSD": Currency{ "BSD", 44, 2, "BSD" },
+    "BTN": Currency{ "BTN", 64, 2, "BTN" },
+    "BWP": Currency{ "BWP", 72, 2, "BWP" },
+    "BYN": Currency{ "BYN", 933, 2, "BYN" },
+    "BZD": Currency{ "BZD", 84, 2, "BZD" },
+    "CAD": Currency{ "CAD", 124, 2, "CA$" },
+    "CDF": Currency{ "CDF", 976, 2, "CDF" },
+    "CHE": Currency{ "CHE", 947, 2, "CHE" },
+    "CHF": Currency{ "CHF", 756, 2, "CHF" },
+    "CHW": Currency{ "CHW", 948, 2, "CHW" },
+    "CLF": Currency{ "CLF", 990, 4, "CLF" },
+    "CLP": Currency{ "CLP", 152, 0, "CLP" },
+    "CNY": Currency{ "CNY", 156, 2, "CN¥" },
+    "COP": Currency{ "COP", 170, 2, "COP" },
+    "COU": Currency{ "COU", 970, 2, "COU" },
+    "CRC": Currency{ "CRC", 188, 2, "CRC" },
+    "CUC": Currency{ "CUC", 931, 2, "CUC" },
+    "CUP": Currency{ "CUP", 192, 2, "CUP" },
+    "CVE": Currency{ "CVE", 132, 2, "CVE" },
+    "CZK": Currency{ "CZK", 203, 2, "CZK" },
+    "DJF": Currency{ "DJF", 262, 0, "DJF" },
+    "DKK": Currency{ "DKK", 208, 2, "DKK" },
+    "DOP": Currency{ "DOP", 214, 2, "DOP" },
+    "DZD": Currency{ "DZD", 12, 2, "DZD" },
+    "EGP": Currency{ "EGP", 818, 2, "EGP" },
+    "ERN": Currency{ "ERN", 232, 2, "ERN" },
+    "ETB": Currency{ "ETB", 230, 2, "ETB" },
+    "EUR": Currency{ "EUR", 978, 2, "€" },
+    "FJD": Currency{ "FJD", 242, 2, "FJD" },
+    "FKP": Currency{ "FKP", 238, 2, "FKP" },
+    "GBP": Currency{ "GBP", 826, 2, "£" },
+    "GEL": Currency{ "GEL", 981, 2, "GEL" },
+    "GHS": Currency{ "GHS", 936, 2, "GHS" },
+    "GIP": Currency{ "GIP", 292, 2, "GIP" },
+    "GMD": Currency{ "GMD", 270, 2, "GMD" },
+    "GNF": Currency{ "GNF", 324, 0, "GNF" },
+    "GTQ": Currency{ "GTQ", 320, 2, "GTQ" },
+    "GYD": Currency{ "GYD", 328, 2, "GYD" },
+    "HKD": Currency{ "HKD", 344, 2, "HK$" },
+    "HNL": Currency{ "HNL", 340, 2, "HNL" },
+    "HTG": Currency{ "HTG", 332, 2, "HTG" },
+    "HUF": Currency{ "HUF", 348, 2, "HUF" },
+    "IDR": Currency{ "IDR", 360, 2, "IDR" },
+    "ILS": Currency{ "ILS", 376, 2, "₪" },
+    "INR": Currency{ "INR", 356, 2, "₹" },
+    "IQD": Currency{ "IQD", 368, 3, "IQD" },
+    "IRR": Currency{ "IRR", 364, 2, "IRR" },
+    "ISK": Currency{ "ISK", 352, 0, "ISK" },
+    "JMD": Currency{ "JMD", 388, 2, "JMD" },
+    "JOD": Currency{ "JOD", 400, 3, "JOD" },
+    "JPY": Currency{ "JPY", 392, 0, "¥" },
+    "KES": Currency{ "KES", 404, 2, "KES" },
+    "KGS": Currency{ "KGS", 417, 2, "KGS" },
+    "KHR": Currency{ "KHR", 116, 2, "KHR" },
+    "KMF": Currency{ "KMF", 174, 0, "KMF" },
+    "KPW": Currency{ "KPW", 408, 2, "KPW" },
+    "KRW": Currency{ "KRW", 410, 0, "₩" },
+    "KWD": Currency{ "KWD", 414, 3, "KWD" },
+    "KYD": Currency{ "KYD", 136, 2, "KYD" },
+    "KZT": Currency{ "KZT", 398, 2, "KZT" },
+    "LAK": Currency{ "LAK", 418, 2, "LAK" },
+    "LBP": Currency{ "LBP", 422, 2, "LBP" },
+    "LKR": Currency{ "LKR", 144, 2, "LKR" },
+    "LRD": Currency{ "LRD", 430, 2, "LRD" },
+    "LSL": Currency{ "LSL", 426, 2, "LSL" },
+    "LYD": Currency{ "LYD", 434, 3, "LYD" },
+    "MAD": Currency{ "MAD", 504, 2, "MAD" },
+    "MDL": Currency{ "MDL", 498, 2, "MDL" },
+    "MGA": Currency{ "MGA", 969, 2, "MGA" },
+    "MKD": Currency{ "MKD", 807, 2, "MKD" },
+    "MMK": Currency{ "MMK", 104, 2, "MMK" },
+    "MNT": Currency{ "MNT", 496, 2, "MNT" },
+    "MOP": Currency{ "MOP", 446, 2, "MOP" },
+    "MRU": Currency{ "MRU", 929, 2, "MRU" },
+    "MUR": Currency{ "MUR", 480, 2, "MUR" },
+    "MVR": Currency{ "MVR", 462, 2, "MVR" },
+    "MWK": Currency{ "MWK", 454, 2, "MWK" },
+    "MXN": Currency{ "MXN", 484, 2, "MX$" },
+    "MXV": Currency{ "MXV", 979, 2, "MXV" },
+    "MYR": Currency{ "MYR", 458, 2, "MYR" },
+    "MZN": Currency{ "MZN", 943, 2, "MZN" },
+    "NAD": Currency{ "NAD", 516, 2, "NAD" },
+    "NGN": Currency{ "NGN", 566, 2, "NGN" },
+    "NIO": Currency{ "NIO", 558, 2, "NIO" },
+    "NOK": Currency{ "NOK", 578, 2, "NOK" },
+    "NPR": Currency{ "NPR", 524, 2, "NPR" },
+    "NZD": Currency{ "NZD", 554, 2, "NZ$" },
+    "OMR": Currency{ "OMR", 512, 3, "OMR" },
+    "PAB": Currency{ "PAB", 590, 2, "PAB" },
+    "PEN": Currency{ "PEN", 604, 2, "PEN" },
+    "PGK": Currency{ "PGK", 598, 2, "PGK" },
+    "PHP": Currency{ "PHP", 608, 2, "₱" },
+    "PKR": Currency{ "PKR", 586, 2, "PKR" },
+    "PLN": Currency{ "PLN", 985, 2, "PLN" },
+    "PYG": Currency{ "PYG", 600, 0, "PYG" },
+    "QAR": Currency{ "QAR", 634, 2, "QAR" },
+    "RON": Currency{ "RON", 946, 2, "RON" },
+    "RSD": Currency{ "RSD", 941, 2, "RSD" },
+    "RUB": Currency{ "RUB", 643, 2, "RUB" },
+    "RWF": Currency{ "RWF", 646, 0, "RWF" },
+    "SAR": Currency{ "SAR", 682, 2, "SAR" },
+    "SBD": Currency{ "SBD", 90, 2, "SBD" },
+    "SCR": Currency{ "SCR", 690, 2, "SCR" },
+    "SDG": Currency{ "SDG", 938, 2, "SDG" },
+    "SEK": Currency{ "SEK", 752, 2, "SEK" },
+    "SGD": Currency{ "SGD", 702, 2, "SGD" },
+    "SHP": Currency{ "SHP", 654, 2, "SHP" },
+    "SLE": Currency{ "SLE", 925, 2, "SLE" },
+    "SLL": Currency{ "SLL", 694, 2, "SLL" },
+    "SOS": Currency{ "SOS", 706, 2, "SOS" },
+    "SRD": Currency{ "SRD", 968, 2, "SRD" },
+    "SSP": Currency{ "SSP", 728, 2, "SSP" },
+    "STN": Currency{ "STN", 930, 2, "STN" },
+    "SVC": Currency{ "SVC", 222, 2, "SVC" },
+    "SYP": Currency{ "SYP", 760, 2, "SYP" },
+    "SZL": Currency{ "SZL", 748, 2, "SZL" },
+    "THB": Currency{ "THB", 764, 2, "THB" },
+    "TJS": Currency{ "TJS", 972, 2, "TJS" },
+    "TMT": Currency{ "TMT", 934, 2, "TMT" },
+    "TND": Currency{ "TND", 788, 3, "TND" },
+    "TOP": Currency{ "TOP", 776, 2, "TOP" },
+    "TRY": Currency{ "TRY", 949, 2, "TRY" },
+    "TTD": Currency{ "TTD", 780, 2, "TTD" },
+    "TWD": Currency{ "TWD", 901, 2, "NT$" },
+    "TZS": Currency{ "TZS", 834, 2, "TZS" },
+    "UAH": Currency{ "UAH", 980, 2, "UAH" },
+    "UGX": Currency{ "UGX", 800, 0, "UGX" },
+    "USD": Currency{ "USD", 840, 2, "$" },
+    "USN": Currency{ "USN", 997, 2, "USN" },
+    "UYI": Currency{ "UYI", 940, 0, "UYI" },
+    "UYU": Currency{ "UYU", 858, 2, "UYU" },
+    "UYW": Currency{ "UYW", 927, 4, "UYW" },
+    "UZS": Currency{ "UZS", 860, 2, "UZS" },
+    "VED": Currency{ "VED", 926, 2, "VED" },
+    "VES": Currency{ "VES", 928, 2, "VES" },
+    "VND": Currency{ "VND", 704, 0, "₫" },
+    "VUV": Currency{ "VUV", 548, 0, "VUV" },
+    "WST": Currency{ "WST", 882, 2, "WST" },
+    "XAF": Currency{ "XAF", 950, 0, "FCFA" },
+    "XCD": Currency{ "XCD", 951, 2, "EC$" },
+    "XOF": Currency{ "XOF", 952, 0, "F CFA" },
+    "XPF": Currency{ "XPF", 953, 0, "CFPF" },
+    "YER": Currency{ "YER", 886, 2, "YER" },
+    "ZAR": Currency{ "ZAR", 710, 2, "ZAR" },
+    "ZMW": Currency{ "ZMW", 967, 2, "ZMW" },
+    "ZWG": Currency{ "ZWG", 924, 2, "ZWG" },
+    "ZWL": Currency{ "ZWL", 932, 2, "ZWL" },
+}
@@ -0,0 +1,254 @@
+/*
+ * langtag.go - BCP 47 language tags
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "strings"
+)
+
+// invalid language tag
+var ErrLanguageTag = errors.New("godec64: invalid language tag")
+
+// BCP 47 language tag. Only parts used to choose locale formatting are kept
+type LanguageTag struct {
+    Language string // lowercase, "und" for root locale
+    Script string   // titlecase, for example "Latn"
+    Region string   // uppercase or 3 digits, for example "CH" or "419"
+    Variants []string
+    // numbering system from Unicode locale extension (-u-nu-)
+    NumberingSystem string
+}
+
+// POSIX locale modifiers that choose script
+var posixScriptModifiers map[string]string = map[string]string {
+    "latin": "Latn",
+    "cyrillic": "Cyrl",
+    "devanagari": "Deva",
+}
+
+func isAlphaSubtag(s string) bool {
+    for i := 0; i < len(s); i++ {
+        c := s[i] | 0x20
+        if c<'a' || c>'z' { return false }
+    }
+    return true
+}
+
+func isDigitSubtag(s string) bool {
+    for i := 0; i < len(s); i++ {
+        if s[i]<'0' || s[i]>'9' { return false }
+    }
+    return true
+}
+
+func isAlnumSubtag(s string) bool {
+    for i := 0; i < len(s); i++ {
+        c := s[i] | 0x20
+        if (c<'a' || c>'z') && (s[i]<'0' || s[i]>'9') { return false }
+    }
+    return true
+}
+
+// parse Unicode locale extension subtags (after 'u' singleton), returns
+// numbering system and number of consumed subtags
+func parseUnicodeExtension(subtags []string) (string, int, error) {
+    nu := ""
+    i := 0
+    // attributes
+    for i < len(subtags) && len(subtags[i])>=3 && len(subtags[i])<=8 {
+        i++
+    }
+    // keywords: key (2 characters) and types (3-8 characters)
+    for i < len(subtags) && len(subtags[i])==2 {
+        key := subtags[i]
+        if key[1]>='0' && key[1]<='9' {
+            return "", i, ErrLanguageTag
+        }
+        i++
+        start := i
+        for i < len(subtags) && len(subtags[i])>=3 && len(subtags[i])<=8 {
+            i++
+        }
+        if key=="nu" {
+            if i-start!=1 { return "", i, ErrLanguageTag }
+            nu = subtags[start]
+        }
+    }
+    if i==0 { return "", i, ErrLanguageTag }
+    return nu, i, nil
+}
+
+// parse BCP 47 language tag (for example "sr-Latn-RS", "zh-Hant-TW" or
+// "ar-u-nu-latn"). POSIX locale names (for example "pl_PL.UTF-8",
+// "sr_RS@latin", "C") are also accepted. Case is canonicalized and
+// deprecated language codes are replaced. Extensions other than numbering
+// system and private use subtags are skipped. If tag is invalid then
+// ErrLanguageTag is returned with parts parsed before invalid subtag
+func ParseLanguageTag(tag string) (LanguageTag, error) {
+    var lt LanguageTag
+    lt.Language = "und"
+    // POSIX codeset and modifier
+    if i := strings.IndexAny(tag, ".@"); i >= 0 {
+        if j := strings.IndexByte(tag[i:], '@'); j >= 0 {
+            lt.Script = posixScriptModifiers[strings.ToLower(tag[i+j+1:])]
+        }
+        tag = tag[:i]
+    }
+    if tag=="" || tag=="C" || tag=="POSIX" {
+        return lt, nil
+    }
+    subtags := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+        return r=='-' || r=='_'
+    })
+    if len(subtags)==0 || strings.Count(tag, "-")+strings.Count(tag, "_") !=
+                len(subtags)-1 {
+        return lt, ErrLanguageTag
+    }
+    for _, st := range subtags {
+        if len(st)>8 || !isAlnumSubtag(st) { return lt, ErrLanguageTag }
+    }
+
+    // language
+    lang := subtags[0]
+    if !isAlphaSubtag(lang) || len(lang)<2 || (len(lang)==4 && lang!="root") {
+        return lt, ErrLanguageTag
+    }
+    i := 1
+    if len(subtags)>1 {
+        if alias, ok := localeAliases[lang+"-"+subtags[1]]; ok {
+            lang = alias
+            i++
+        }
+    }
+    if alias, ok := localeAliases[lang]; ok {
+        lang = alias
+    }
+    // alias can have script and region (sh -> sr-Latn), used if tag has not own
+    aliasScript, aliasRegion := "", ""
+    if j := strings.IndexByte(lang, '-'); j >= 0 {
+        for _, st := range strings.Split(lang[j+1:], "-") {
+            if len(st)==4 {
+                aliasScript = st
+            } else {
+                aliasRegion = st
+            }
+        }
+        lang = lang[:j]
+    }
+    if lang!="root" {
+        lt.Language = lang
+    }
+    // extended language subtag replaces primary language (zh-yue -> yue)
+    if i < len(subtags) && len(lang)<=3 && len(subtags[i])==3 &&
+                isAlphaSubtag(subtags[i]) {
+        lt.Language = subtags[i]
+        i++
+    }
+    // script
+    if i < len(subtags) && len(subtags[i])==4 && isAlphaSubtag(subtags[i]) {
+        lt.Script = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+        i++
+    }
+    // region
+    if i < len(subtags) && ((len(subtags[i])==2 && isAlphaSubtag(subtags[i])) ||
+                (len(subtags[i])==3 && isDigitSubtag(subtags[i]))) {
+        lt.Region = strings.ToUpper(subtags[i])
+        i++
+    }
+    if lt.Script=="" {
+        lt.Script = aliasScript
+    }
+    if lt.Region=="" {
+        lt.Region = aliasRegion
+    }
+    // variants
+    for i < len(subtags) && (len(subtags[i])>=5 ||
+                (len(subtags[i])==4 && subtags[i][0]>='0' && subtags[i][0]<='9')) {
+        lt.Variants = append(lt.Variants, subtags[i])
+        i++
+    }
+    // extensions
+    for i < len(subtags) {
+        if len(subtags[i])!=1 { return lt, ErrLanguageTag }
+        singleton := subtags[i][0]
+        i++
+        if singleton=='x' {
+            // private use, ignored
+            if i==len(subtags) { return lt, ErrLanguageTag }
+            break
+        }
+        if singleton=='u' {
+            nu, n, err := parseUnicodeExtension(subtags[i:])
+            if err!=nil { return lt, err }
+            lt.NumberingSystem = nu
+            i += n
+            continue
+        }
+        start := i
+        for i < len(subtags) && len(subtags[i])>=2 {
+            i++
+        }
+        if i==start { return lt, ErrLanguageTag }
+    }
+    return lt, nil
+}
+
+// CLDR locale identifier (without extensions) used to find locale data
+func (lt LanguageTag) localeID() string {
+    if lt.Language=="und" { return "root" }
+    id := lt.Language
+    if lt.Script!="" {
+        id += "-" + lt.Script
+    }
+    if lt.Region!="" {
+        id += "-" + lt.Region
+    }
+    for _, v := range lt.Variants {
+        id += "-" + v
+    }
+    return id
+}
+
+// return canonical form of language tag
+func (lt LanguageTag) String() string {
+    var sb strings.Builder
+    sb.WriteString(lt.Language)
+    if lt.Script!="" {
+        sb.WriteByte('-')
+        sb.WriteString(lt.Script)
+    }
+    if lt.Region!="" {
+        sb.WriteByte('-')
+        sb.WriteString(lt.Region)
+    }
+    for _, v := range lt.Variants {
+        sb.WriteByte('-')
+        sb.WriteString(v)
+    }
+    if lt.NumberingSystem!="" {
+        sb.WriteString("-u-nu-")
+        sb.WriteString(lt.NumberingSystem)
+    }
+    return sb.String()
+}
@@ -0,0 +1,83 @@
+/*
+ * langtag_test.go - BCP 47 language tag tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "testing"
+)
+
+type LanguageTagTC struct {
+    tag string
+    expected string
+    expectedLocale string
+    expectedErr error
+}
+
+func TestParseLanguageTag(t *testing.T) {
+    testCases := []LanguageTagTC {
+        LanguageTagTC{ "en", "en", "en", nil },
+        LanguageTagTC{ "EN-us", "en-US", "en-US", nil },
+        LanguageTagTC{ "pl_PL.UTF-8", "pl-PL", "pl-PL", nil },
+        LanguageTagTC{ "de_CH.UTF-8@euro", "de-CH", "de-CH", nil },
+        LanguageTagTC{ "sr_RS@latin", "sr-Latn-RS", "sr-Latn-RS", nil },
+        LanguageTagTC{ "C", "und", "root", nil },
+        LanguageTagTC{ "POSIX", "und", "root", nil },
+        LanguageTagTC{ "", "und", "root", nil },
+        LanguageTagTC{ "und", "und", "root", nil },
+        LanguageTagTC{ "root", "und", "root", nil },
+        LanguageTagTC{ "zh-hant-tw", "zh-Hant-TW", "zh-Hant-TW", nil },
+        LanguageTagTC{ "sr-Latn-RS", "sr-Latn-RS", "sr-Latn-RS", nil },
+        LanguageTagTC{ "es-419", "es-419", "es-419", nil },
+        LanguageTagTC{ "de-CH-1996", "de-CH-1996", "de-CH-1996", nil },
+        LanguageTagTC{ "sl-rozaj-biske", "sl-rozaj-biske", "sl-rozaj-biske", nil },
+        LanguageTagTC{ "zh-yue-HK", "yue-HK", "yue-HK", nil },
+        LanguageTagTC{ "iw-IL", "he-IL", "he-IL", nil },
+        LanguageTagTC{ "no-bok", "nb", "nb", nil },
+        LanguageTagTC{ "sh", "sr-Latn", "sr-Latn", nil },
+        LanguageTagTC{ "sh-RS", "sr-Latn-RS", "sr-Latn-RS", nil },
+        LanguageTagTC{ "sh-Cyrl-RS", "sr-Cyrl-RS", "sr-Cyrl-RS", nil },
+        LanguageTagTC{ "sh_RS@cyrillic", "sr-Cyrl-RS", "sr-Cyrl-RS", nil },
+        LanguageTagTC{ "ar-u-nu-latn", "ar-u-nu-latn", "ar", nil },
+        LanguageTagTC{ "hi-IN-u-nu-deva", "hi-IN-u-nu-deva", "hi-IN", nil },
+        LanguageTagTC{ "th-TH-u-ca-buddhist-nu-thai", "th-TH-u-nu-thai", "th-TH", nil },
+        LanguageTagTC{ "de-DE-u-co-phonebk", "de-DE", "de-DE", nil },
+        LanguageTagTC{ "en-a-bbb-u-nu-arab-x-private", "en-u-nu-arab", "en", nil },
+        LanguageTagTC{ "en-x-nu-arab", "en", "en", nil },
+        LanguageTagTC{ "en-US-u", "en-US", "en-US", ErrLanguageTag },
+        LanguageTagTC{ "en--US", "und", "root", ErrLanguageTag },
+        LanguageTagTC{ "en-US-", "und", "root", ErrLanguageTag },
+        LanguageTagTC{ "e", "und", "root", ErrLanguageTag },
+        LanguageTagTC{ "en US", "und", "root", ErrLanguageTag },
+        LanguageTagTC{ "en-US-toolongsubtag", "und", "root", ErrLanguageTag },
+        LanguageTagTC{ "en-US-u-nu", "en-US", "en-US", ErrLanguageTag },
+        LanguageTagTC{ "fr-CA-xx", "fr-CA", "fr-CA", ErrLanguageTag },
+    }
+    for i, tc := range testCases {
+        result, err := ParseLanguageTag(tc.tag)
+        if tc.expected!=result.String() || tc.expectedLocale!=result.localeID() ||
+                tc.expectedErr!=err {
+            t.Errorf("Result mismatch: %d: parse(%v)->%v,%v,%v!=%v,%v,%v",
+                     i, tc.tag, tc.expected, tc.expectedLocale, tc.expectedErr,
+                     result.String(), result.localeID(), err)
+        }
+    }
+}
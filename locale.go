@@ -23,12 +23,16 @@
 package godec64
 
 import (
-    "bytes"
-    "strings"
+    "errors"
     "strconv"
+    "strings"
+    "sync"
     "unicode/utf8"
 )
 
+// invalid locale formatting info
+var ErrLocFmt = errors.New("godec64: invalid locale formatting info")
+
 // locale formatting info
 type LocFmt struct {
     Comma, Sep1000, Sep1000_2 rune
@@ -36,168 +40,195 @@ type LocFmt struct {
     Digits []rune
 }
 
-var normalDigits []rune = []rune("0123456789")
-var arDigits []rune = []rune("٠١٢٣٤٥٦٧٨٩")
-var faDigits []rune = []rune("۰۱۲۳۴۵۶۷۸۹")
-var bnDigits []rune = []rune("০১২৩৪৫৬৭৮৯")
-var mrDigits []rune = []rune("०१२३४५६७८९")
-var myDigits []rune = []rune("၀၁၂၃၄၅၆၇၈၉")
+//go:generate go run gen_locales.go
 
-var defaultLocaleFormat LocFmt = LocFmt{ '.', ',', ',', false, normalDigits }
+var defaultLocaleFormat LocFmt = LocFmt{ '.', ',', ',', false, latnDigits }
+
+// locale formatting info registered at runtime, override built-in table
+var (
+    registeredLocalesMutex sync.RWMutex
+    registeredLocales map[string]LocFmt = make(map[string]LocFmt)
+)
 
-var localeFormats map[string]LocFmt = map[string]LocFmt {
-    "af": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "am": LocFmt{ '.', ',', ',', false, normalDigits },
-    "ar": LocFmt{ '٫', '٬', '٬', false, arDigits },
-    "az": LocFmt{ ',', '.', '.', false, normalDigits },
-    "bg": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "bn": LocFmt{ '.', ',', ',', true, bnDigits },
-    "ca": LocFmt{ ',', '.', '.',false, normalDigits },
-    "cs": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "da": LocFmt{ ',', '.', '.', false, normalDigits },
-    "de": LocFmt{ ',', '.', '.', false, normalDigits },
-    "el": LocFmt{ ',', '.', '.', false, normalDigits },
-    "en": LocFmt{ '.', ',', ',', false, normalDigits },
-    "es": LocFmt{ ',', '.', '.', false, normalDigits },
-    "et": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "fa": LocFmt{ '٫', '٬', '٬', false, faDigits },
-    "fi": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "fil": LocFmt{ '.', ',', ',', false, normalDigits },
-    "fr": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "gu": LocFmt{ '.', ',', ',', true, normalDigits },
-    "he": LocFmt{ '.', ',', ',', false, normalDigits },
-    "hi": LocFmt{ '.', ',', ',', true, normalDigits },
-    "hr": LocFmt{ ',', '.', '.', false, normalDigits },
-    "hu": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "hy": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "id": LocFmt{ ',', '.', '.', false, normalDigits },
-    "is": LocFmt{ ',', '.', '.', false, normalDigits },
-    "it": LocFmt{ ',', '.', '.', false, normalDigits },
-    "ja": LocFmt{ '.', ',', ',', false, normalDigits },
-    "ka": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "kk": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "km": LocFmt{ ',', '.', '.', false, normalDigits },
-    "kn": LocFmt{ '.', ',', ',', false, normalDigits },
-    "ko": LocFmt{ '.', ',', ',', false, normalDigits },
-    "ky": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "lo": LocFmt{ ',', '.', '.', false, normalDigits },
-    "lt": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "lv": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "mk": LocFmt{ ',', '.', '.', false, normalDigits },
-    "ml": LocFmt{ '.', ',', ',', true, normalDigits },
-    "mn": LocFmt{ '.', ',', ',', false, normalDigits },
-    "mo": LocFmt{ ',', '.', '.', false, normalDigits },
-    "mr": LocFmt{ '.', ',', ',', true, mrDigits },
-    "ms": LocFmt{ '.', ',', ',', false, normalDigits },
-    "mul": LocFmt{ '.', ',', ',', false, normalDigits },
-    "my": LocFmt{ '.', ',', ',', false, myDigits },
-    "nb": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "ne": LocFmt{ '.', ',', ',', false, mrDigits },
-    "nl": LocFmt{ ',', '.', '.', false, normalDigits },
-    "no": LocFmt{ '.', ',', ',', false, normalDigits },
-    "pa": LocFmt{ '.', ',', ',', true, normalDigits },
-    "pl": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "pt": LocFmt{ ',', '.', '.', false, normalDigits },
-    "ro": LocFmt{ ',', '.', '.', false, normalDigits },
-    "ru": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "sh": LocFmt{ ',', '.', '.', false, normalDigits },
-    "si": LocFmt{ '.', ',', ',', false, normalDigits },
-    "sk": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "sl": LocFmt{ ',', '.', '.', false, normalDigits },
-    "sq": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "sr": LocFmt{ ',', '.', '.', false, normalDigits },
-    "sv": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "sw": LocFmt{ '.', ',', ',', false, normalDigits },
-    "ta": LocFmt{ '.', ',', ',', true, normalDigits },
-    "te": LocFmt{ '.', ',', ',', false, normalDigits },
-    "th": LocFmt{ '.', ',', ',', false, normalDigits },
-    "tl": LocFmt{ '.', ',', ',', false, normalDigits },
-    "tn": LocFmt{ '.', ',', ',', false, normalDigits },
-    "tr": LocFmt{ ',', '.', '.', false, normalDigits },
-    "uk": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "ur": LocFmt{ '.', ',', ',', false, normalDigits },
-    "uz": LocFmt{ ',', ' ', ' ', false, normalDigits },
-    "vi": LocFmt{ ',', '.', '.', false, normalDigits },
-    "zh": LocFmt{ '.', ',', ',', false, normalDigits },
-    "zu": LocFmt{ '.', ',', ',', false, normalDigits },
+// check locale formatting info: digits must be 10 distinct characters
+// different from comma and separators, comma must be different from separators
+func checkLocFmt(l *LocFmt) error {
+    if len(l.Digits)!=10 || l.Comma==l.Sep1000 || l.Comma==l.Sep1000_2 {
+        return ErrLocFmt
+    }
+    for i, d := range l.Digits {
+        if d==l.Comma || d==l.Sep1000 || d==l.Sep1000_2 || !utf8.ValidRune(d) {
+            return ErrLocFmt
+        }
+        for _, d2 := range l.Digits[:i] {
+            if d==d2 { return ErrLocFmt }
+        }
+    }
+    return nil
 }
 
-// get locale formating info
-func GetLocFmt(lang string) *LocFmt {
-    outLang := lang
-    langSlen := len(lang)
-    if langSlen>=3 && (lang[2]=='_' || lang[2]=='-') {
-        outLang = lang[0:2]
-    } else if langSlen>=4 && (lang[3]=='_' || lang[3]=='-') {
-        outLang = lang[0:3]
+// key of registered locale: CLDR locale identifier with numbering system
+func registeredLocaleKey(tag string) (string, error) {
+    lt, err := ParseLanguageTag(tag)
+    if err!=nil { return "", err }
+    key := lt.localeID()
+    if lt.NumberingSystem!="" {
+        if _, ok := numberingSystems[lt.NumberingSystem]; !ok {
+            return "", ErrLanguageTag
+        }
+        key += "-u-nu-" + lt.NumberingSystem
     }
-    l, ok := localeFormats[outLang]
-    if !ok { l = defaultLocaleFormat }
-    return &l
+    return key, nil
 }
 
-// format 64-bit decimal fixed point including locale
-func (a UDec64) LocaleFormatNewBytes(lang string, precision, displayPrecision uint,
-                                trimZeroes, noSep1000 bool) []byte {
-    l := GetLocFmt(lang)
-    s := a.FormatNewBytes(precision, displayPrecision, trimZeroes)
-    slen := len(s)
-    os := make([]byte, slen<<1) // optimization
-    oslen := 0
-    commaIdx := bytes.LastIndexByte(s, '.')
-    if commaIdx==-1 {
-        commaIdx = slen
+// register locale formatting info for language tag (for example "en-CH" or
+// "en-u-nu-thai" to override only formatting with that numbering system).
+// Registered locale overrides built-in locale and is also used by child
+// locales (en-GB after registering en). Safe for concurrent use with
+// formatting and parsing
+func RegisterLocale(tag string, l LocFmt) error {
+    key, err := registeredLocaleKey(tag)
+    if err!=nil { return err }
+    if err := checkLocFmt(&l); err!=nil { return err }
+    l.Digits = append([]rune(nil), l.Digits...)
+    registeredLocalesMutex.Lock()
+    registeredLocales[key] = l
+    registeredLocalesMutex.Unlock()
+    return nil
+}
+
+// unregister locale formatting info registered by RegisterLocale, returns
+// true if locale was registered
+func UnregisterLocale(tag string) bool {
+    key, err := registeredLocaleKey(tag)
+    if err!=nil { return false }
+    registeredLocalesMutex.Lock()
+    _, ok := registeredLocales[key]
+    delete(registeredLocales, key)
+    registeredLocalesMutex.Unlock()
+    return ok
+}
+
+// lookup registered or built-in locale formatting info.
+// Must be called with registeredLocalesMutex locked for reading
+func lookupLocFmt(locale string) (LocFmt, bool) {
+    if l, ok := registeredLocales[locale]; ok {
+        return l, true
     }
-    ti := commaIdx
-    i := commaIdx
-    if !l.Sep100and1000 {
-        ti = (commaIdx)%3
-        if ti==0 { ti=3 }
+    l, ok := localeFormats[locale]
+    return l, ok
+}
+
+// get parent of CLDR locale identifier
+func localeParent(locale string) string {
+    if p, ok := localeParents[locale]; ok {
+        return p
     }
-    for k:=0; k < commaIdx; k++ {
-        r := s[k]
-        if r>='0' && r<='9' {
-            if oslen+4 >= len(os) {
-                os = append(os, 0,0,0,0)
-            }
-            oslen += utf8.EncodeRune(os[oslen:], l.Digits[r-'0'])
-        }
-        if !noSep1000 && i!=1 {
-            if !l.Sep100and1000 || ti<=3 {
-                ti--
-                if ti==0 {
-                    if oslen+4 >= len(os) {
-                        os = append(os, 0,0,0,0)
-                    }
-                    oslen += utf8.EncodeRune(os[oslen:], l.Sep1000)
-                    ti = 3
-                }
-            } else {
-                ti--
-                if (ti-3)&1==0 {
-                    if oslen+4 >= len(os) {
-                        os = append(os, 0,0,0,0)
-                    }
-                    oslen += utf8.EncodeRune(os[oslen:], l.Sep1000)
-                }
-            }
-        }
-        i--
+    if i := strings.LastIndexByte(locale, '-'); i >= 0 {
+        return locale[:i]
     }
-    // comma
-    if commaIdx!=slen {
-        if oslen+4 >= len(os) {
-            os = append(os, 0,0,0,0)
+    return "root"
+}
+
+// find native numbering system of locale, returns empty string if it is same
+// as default numbering system
+func findNativeNumbering(locale string) string {
+    for ; ; locale = localeParent(locale) {
+        if ns, ok := localeNativeNumbering[locale]; ok {
+            return ns
         }
-        oslen += utf8.EncodeRune(os[oslen:], l.Comma)
-        for i = commaIdx+1; i < slen; i++ {
-            if oslen+4 >= len(os) {
-                os = append(os, 0,0,0,0)
+        if _, ok := lookupLocFmt(locale); ok || locale=="root" { break }
+    }
+    return ""
+}
+
+// find locale formatting info for language tag, following CLDR parent
+// chain (for example de-CH-1996 -> de-CH, es-MX -> es-419, nb -> no).
+// Numbering system (if not empty, otherwise from -u-nu- extension) replaces
+// digits of locale. "native" selects native numbering system of locale
+func findLocFmt(lang, numberingSystem string) LocFmt {
+    // invalid tags are handled by using parts parsed before invalid subtag
+    tag, _ := ParseLanguageTag(lang)
+    if numberingSystem=="" {
+        numberingSystem = tag.NumberingSystem
+    }
+    locale := tag.localeID()
+    registeredLocalesMutex.RLock()
+    defer registeredLocalesMutex.RUnlock()
+    if numberingSystem=="native" {
+        numberingSystem = findNativeNumbering(locale)
+    }
+    nuDigits, nuOk := numberingSystems[numberingSystem]
+    l := defaultLocaleFormat
+    for ; ; locale = localeParent(locale) {
+        if nuOk {
+            nuLocale := locale+"-u-nu-"+numberingSystem
+            if nl, ok := registeredLocales[nuLocale]; ok {
+                return nl
             }
-            oslen += utf8.EncodeRune(os[oslen:], l.Digits[s[i]-'0'])
+            if nl, ok := localeNumberingFormats[nuLocale]; ok {
+                return nl
+            }
+        }
+        if ll, ok := lookupLocFmt(locale); ok {
+            l = ll
+            break
         }
+        if locale=="root" { break }
+    }
+    if nuOk {
+        l.Digits = nuDigits
+    }
+    return l
+}
+
+// get locale formating info. Language can be BCP 47 language tag (with
+// script, region and -u-nu- numbering system extension, for example
+// "sr-Latn-RS" or "ar-u-nu-latn") or POSIX locale name (for example pl_PL.UTF-8)
+func GetLocFmt(lang string) *LocFmt {
+    l := findLocFmt(lang, "")
+    return &l
+}
+
+// get locale formating info with digits of CLDR numbering system (for example
+// "latn", "thai", "tamldec" or "native"). Empty or unknown numbering system
+// gives same result as GetLocFmt
+func GetLocFmtNumbering(lang, numberingSystem string) *LocFmt {
+    l := findLocFmt(lang, numberingSystem)
+    return &l
+}
+
+// returns true if numbering system is known CLDR decimal numbering system
+func IsNumberingSystem(numberingSystem string) bool {
+    _, ok := numberingSystems[numberingSystem]
+    return ok
+}
+
+// legacy locale formatting options
+func legacyLocaleFormatOptions(precision, displayPrecision uint,
+                               trimZeroes, noSep1000 bool,
+                               mode RoundingMode) FormatOptions {
+    opts := legacyFormatOptions(precision, displayPrecision, trimZeroes)
+    opts.Rounding = mode
+    if noSep1000 {
+        opts.Grouping = GroupingOff
     }
-    return os[:oslen]
+    return opts
+}
+
+// format 64-bit decimal fixed point including locale
+func (a UDec64) LocaleFormatNewBytes(lang string, precision, displayPrecision uint,
+                                trimZeroes, noSep1000 bool) []byte {
+    return a.LocaleFormatOptsBytes(lang, precision, legacyLocaleFormatOptions(
+                    precision, displayPrecision, trimZeroes, noSep1000, RoundDown))
+}
+
+// format 64-bit decimal fixed point including locale with rounding mode.
+// If displayPrecision is lower than precision then value will be rounded
+func (a UDec64) LocaleFormatNewBytesR(lang string, precision, displayPrecision uint,
+                                trimZeroes, noSep1000 bool, mode RoundingMode) []byte {
+    return a.LocaleFormatOptsBytes(lang, precision, legacyLocaleFormatOptions(
+                    precision, displayPrecision, trimZeroes, noSep1000, mode))
 }
 
 func (a UDec64) LocaleFormatBytes(lang string, precision uint,
@@ -208,50 +239,16 @@ func (a UDec64) LocaleFormatBytes(lang string, precision uint,
 // format 64-bit decimal fixed point including locale
 func (a UDec64) LocaleFormatNew(lang string, precision, displayPrecision uint,
                             trimZeroes, noSep1000 bool) string {
-    l := GetLocFmt(lang)
-    s := a.FormatNewBytes(precision, displayPrecision, trimZeroes)
-    var os strings.Builder
-    slen := len(s)
-    os.Grow(slen*3)
-    commaIdx := bytes.LastIndexByte(s, '.')
-    if commaIdx==-1 {
-        commaIdx = slen
-    }
-    ti := commaIdx
-    i := commaIdx
-    if !l.Sep100and1000 {
-        ti = (commaIdx)%3
-        if ti==0 { ti=3 }
-    }
-    for k:=0; k < commaIdx; k++ {
-        r := s[k]
-        if r>='0' && r<='9' {
-            os.WriteRune(l.Digits[r-'0'])
-        }
-        if !noSep1000 && i!=1 {
-            if !l.Sep100and1000 || ti<=3 {
-                ti--
-                if ti==0 {
-                    os.WriteRune(l.Sep1000)
-                    ti = 3
-                }
-            } else {
-                ti--
-                if (ti-3)&1==0 {
-                    os.WriteRune(l.Sep1000)
-                }
-            }
-        }
-        i--
-    }
-    // comma
-    if commaIdx!=slen {
-        os.WriteRune(l.Comma)
-        for i = commaIdx+1; i < slen; i++ {
-            os.WriteRune(l.Digits[s[i]-'0'])
-        }
-    }
-    return os.String()
+    return a.LocaleFormatOpts(lang, precision, legacyLocaleFormatOptions(
+                    precision, displayPrecision, trimZeroes, noSep1000, RoundDown))
+}
+
+// format 64-bit decimal fixed point including locale with rounding mode.
+// If displayPrecision is lower than precision then value will be rounded
+func (a UDec64) LocaleFormatNewR(lang string, precision, displayPrecision uint,
+                            trimZeroes, noSep1000 bool, mode RoundingMode) string {
+    return a.LocaleFormatOpts(lang, precision, legacyLocaleFormatOptions(
+                    precision, displayPrecision, trimZeroes, noSep1000, mode))
 }
 
 func (a UDec64) LocaleFormat(lang string, precision uint,
@@ -261,10 +258,23 @@ func (a UDec64) LocaleFormat(lang string, precision uint,
 
 // parse decimal fixed point from string and return value and error (nil if no error)
 func LocaleParseUDec64(lang, str string, precision uint, rounding bool) (UDec64, error) {
-    l := GetLocFmt(lang)
+    return localeParseUDec64(GetLocFmt(lang), str, precision, rounding)
+}
+
+// parse decimal fixed point from string using digits of numbering system
+// (for example "thai"), digits 0-9 are always accepted
+func LocaleParseUDec64Numbering(lang, numberingSystem, str string, precision uint,
+                                rounding bool) (UDec64, error) {
+    return localeParseUDec64(GetLocFmtNumbering(lang, numberingSystem), str,
+                             precision, rounding)
+}
+
+func localeParseUDec64(l *LocFmt, str string, precision uint,
+                       rounding bool) (UDec64, error) {
     if len(str)==0 { return 0, strconv.ErrSyntax }
     
-    os := make([]byte, 0, len(str))
+    var osBuf [64]byte
+    os := osBuf[:0]
     for _, r := range str {
         if r>='0' && r<='9' {
             // if standard digits
@@ -292,10 +302,23 @@ func LocaleParseUDec64(lang, str string, precision uint, rounding bool) (UDec64,
 // parse decimal fixed point from string and return value and error (nil if no error)
 func LocaleParseUDec64Bytes(lang string, strInput []byte,
                              precision uint, rounding bool) (UDec64, error) {
-    l := GetLocFmt(lang)
+    return localeParseUDec64Bytes(GetLocFmt(lang), strInput, precision, rounding)
+}
+
+// parse decimal fixed point from bytes using digits of numbering system
+// (for example "thai"), digits 0-9 are always accepted
+func LocaleParseUDec64NumberingBytes(lang, numberingSystem string, strInput []byte,
+                                precision uint, rounding bool) (UDec64, error) {
+    return localeParseUDec64Bytes(GetLocFmtNumbering(lang, numberingSystem),
+                                  strInput, precision, rounding)
+}
+
+func localeParseUDec64Bytes(l *LocFmt, strInput []byte, precision uint,
+                            rounding bool) (UDec64, error) {
     if len(strInput)==0 { return 0, strconv.ErrSyntax }
     
-    os := make([]byte, 0, len(strInput))
+    var osBuf [64]byte
+    os := osBuf[:0]
     str := strInput
     for len(str)>0 {
         r, size := utf8.DecodeRune(str)
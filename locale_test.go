@@ -23,6 +23,7 @@ package godec64
 
 import (
     "strconv"
+    "sync"
     "testing"
 )
 
@@ -76,7 +77,7 @@ func TestUDec64LocaleFormat(t *testing.T) {
         UDec64LocTC{ "fil", false, 0xab54a98ceb1f0ad3,
                 10, false, "1,234,567,890.1234567891" },
         UDec64LocTC{ "fr", false, 0xab54a98ceb1f0ad3,
-                10, false, "1 234 567 890,1234567891" },
+                10, false, "1 234 567 890,1234567891" },
         UDec64LocTC{ "gu", false, 0xab54a98ceb1f0ad3,
                 10, false, "1,23,45,67,890.1234567891" },
         UDec64LocTC{ "he", false, 0xab54a98ceb1f0ad3,
@@ -138,7 +139,7 @@ func TestUDec64LocaleFormat(t *testing.T) {
         UDec64LocTC{ "nl", false, 0xab54a98ceb1f0ad3,
                 10, false, "1.234.567.890,1234567891" },
         UDec64LocTC{ "no", false, 0xab54a98ceb1f0ad3,
-                10, false, "1,234,567,890.1234567891" },
+                10, false, "1 234 567 890,1234567891" },
         UDec64LocTC{ "pa", false, 0xab54a98ceb1f0ad3,
                 10, false, "1,23,45,67,890.1234567891" },
         UDec64LocTC{ "pl", false, 0xab54a98ceb1f0ad3,
@@ -237,6 +238,129 @@ func TestUDec64LocaleFormat(t *testing.T) {
     }
 }
 
+func TestUDec64LocaleFormatRegional(t *testing.T) {
+    testCases := []UDec64LocTC {
+        UDec64LocTC{ "de", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "de-CH", false, 123456789, 2, false, "1’234’567.89" },
+        UDec64LocTC{ "de_CH.UTF-8", false, 123456789, 2, false, "1’234’567.89" },
+        UDec64LocTC{ "de-ch", false, 123456789, 2, false, "1’234’567.89" },
+        UDec64LocTC{ "de-CH-1996", false, 123456789, 2, false, "1’234’567.89" },
+        UDec64LocTC{ "de-AT", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "de-DE", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "es", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "es-MX", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "es-419", false, 123456789, 2, false, "1,234,567.89" },
+        // es-PE has no own data: es-PE -> es-419
+        UDec64LocTC{ "es-PE", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "es-AR", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "en-ZA", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "en-IN", false, 123456789, 2, false, "12,34,567.89" },
+        // en-GB -> en-001 -> en
+        UDec64LocTC{ "en-GB", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "pt", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "pt-BR", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "pt-PT", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        // pt-AO -> pt-PT
+        UDec64LocTC{ "pt-AO", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "nb", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "nn-NO", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "no", false, 123456789, 2, false, "1\u00a0234\u00a0567,89" },
+        UDec64LocTC{ "sr-Latn-RS", false, 123456789, 2, false, "1.234.567,89" },
+        // zh-Hant -> root
+        UDec64LocTC{ "zh-Hant-TW", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "ar", false, 123456789, 2, false, "١٬٢٣٤٬٥٦٧٫٨٩" },
+        UDec64LocTC{ "ar-EG", false, 123456789, 2, false, "١٬٢٣٤٬٥٦٧٫٨٩" },
+        UDec64LocTC{ "ar-MA", false, 123456789, 2, false, "1.234.567,89" },
+        // deprecated codes
+        UDec64LocTC{ "iw", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "in-ID", false, 123456789, 2, false, "1.234.567,89" },
+        UDec64LocTC{ "POSIX", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "xx-YY", false, 123456789, 2, false, "1,234,567.89" },
+        // numbering system extension
+        UDec64LocTC{ "ar-u-nu-latn", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "ar-EG-u-nu-latn", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "ar-MA-u-nu-arab", false, 123456789, 2, false, "١.٢٣٤.٥٦٧,٨٩" },
+        UDec64LocTC{ "ar-u-nu-unknown", false, 123456789, 2, false, "١٬٢٣٤٬٥٦٧٫٨٩" },
+        UDec64LocTC{ "fa-IR-u-nu-latn", false, 123456789, 2, false, "1,234,567.89" },
+        UDec64LocTC{ "bn-u-nu-latn", false, 123456789, 2, false, "12,34,567.89" },
+        UDec64LocTC{ "hi-IN-u-nu-deva", false, 123456789, 2, false, "१२,३४,५६७.८९" },
+        UDec64LocTC{ "hi-IN-u-nu-latn", false, 123456789, 2, false, "12,34,567.89" },
+        UDec64LocTC{ "th-TH-u-nu-thai", false, 123456789, 2, false, "๑,๒๓๔,๕๖๗.๘๙" },
+        UDec64LocTC{ "de-CH-u-nu-fullwide", false, 123456789, 2, false,
+                "１’２３４’５６７.８９" },
+        UDec64LocTC{ "sr_RS@latin", false, 123456789, 2, false, "1.234.567,89" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.LocaleFormat(tc.lang, tc.precision, tc.trimZeroes, tc.noSep1000)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.trimZeroes, tc.expected, result)
+        }
+    }
+}
+
+type UDec64LocRTC struct {
+    lang string
+    a UDec64
+    precision uint
+    dispPrecision uint
+    mode RoundingMode
+    expected string
+}
+
+func TestUDec64LocaleFormatR(t *testing.T) {
+    testCases := []UDec64LocRTC {
+        UDec64LocRTC{ "de", 1234567995, 3, 2, RoundDown, "1.234.567,99" },
+        UDec64LocRTC{ "de", 1234567995, 3, 2, RoundHalfUp, "1.234.568,00" },
+        UDec64LocRTC{ "en", 999999999, 3, 2, RoundHalfUp, "1,000,000.00" },
+        UDec64LocRTC{ "hi", 9999999999, 3, 1, RoundHalfEven, "1,00,00,000.0" },
+        UDec64LocRTC{ "ar", 9999, 3, 2, RoundHalfUp, "١٠٫٠٠" },
+        UDec64LocRTC{ "bn", 1234565, 3, 2, RoundHalfEven, "১,২৩৪.৫৬" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.LocaleFormatNewR(tc.lang, tc.precision, tc.dispPrecision,
+                        false, false, tc.mode)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.dispPrecision,
+                     tc.expected, result)
+        }
+        resultBytes := tc.a.LocaleFormatNewBytesR(tc.lang, tc.precision,
+                        tc.dispPrecision, false, false, tc.mode)
+        if tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytes(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.dispPrecision,
+                     tc.expected, string(resultBytes))
+        }
+    }
+}
+
+func TestUDec64LocaleFormatNew(t *testing.T) {
+    testCases := []UDec64LocRTC {
+        UDec64LocRTC{ "de", 1234567995, 3, 2, RoundDown, "1.234.567,99" },
+        // zero display precision: legacy routines print one digit after comma
+        UDec64LocRTC{ "de", 1234567995, 3, 0, RoundDown, "1.234.567,0" },
+        UDec64LocRTC{ "en", 0, 3, 0, RoundDown, "0.0" },
+        UDec64LocRTC{ "en", 1234567, 0, 0, RoundDown, "1,234,567" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.LocaleFormatNew(tc.lang, tc.precision, tc.dispPrecision,
+                        false, false)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.dispPrecision,
+                     tc.expected, result)
+        }
+        resultBytes := tc.a.LocaleFormatNewBytes(tc.lang, tc.precision,
+                        tc.dispPrecision, false, false)
+        if tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytes(%v,%s,%v,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.precision, tc.dispPrecision,
+                     tc.expected, string(resultBytes))
+        }
+    }
+}
+
 type UDec64LocParseTC struct {
     lang string
     str string
@@ -263,6 +387,16 @@ func TestUDec64LocaleParse(t *testing.T) {
                 0xab54a98ceb1f0ad3, nil },
         UDec64LocParseTC{ "bn", "১,২৩,৪৫,৬৭,৮৯০.১২৩৪৫৬৭৮৯১", 10, false,
                 0xab54a98ceb1f0ad3, nil },
+        UDec64LocParseTC{ "de-CH", "1'234'567'890.1234567891", 10, false,
+                0xab54a98ceb1f0ad3, nil },
+        UDec64LocParseTC{ "de-CH", "1’234’567’890.1234567891", 10, false,
+                0xab54a98ceb1f0ad3, nil },
+        UDec64LocParseTC{ "fr", "1 234 567 890,1234567891", 10, false,
+                0xab54a98ceb1f0ad3, nil },
+        UDec64LocParseTC{ "hi-IN-u-nu-deva", "१,२३,४५,६७,८९०.१२३४५६७८९१", 10, false,
+                0xab54a98ceb1f0ad3, nil },
+        UDec64LocParseTC{ "ar-u-nu-latn", "1,234,567,890.1234567891", 10, false,
+                0xab54a98ceb1f0ad3, nil },
         UDec64LocParseTC{ "bn", "1,234,567890.1234567891", 10, false,
                 0xab54a98ceb1f0ad3, nil },
     }
@@ -282,3 +416,196 @@ func TestUDec64LocaleParse(t *testing.T) {
         }
     }
 }
+
+type UDec64LocNumberingTC struct {
+    lang string
+    numberingSystem string
+    a UDec64
+    precision uint
+    expected string
+}
+
+func TestUDec64LocaleFormatNumbering(t *testing.T) {
+    testCases := []UDec64LocNumberingTC {
+        UDec64LocNumberingTC{ "en", "", 123456789, 2, "1,234,567.89" },
+        UDec64LocNumberingTC{ "en", "latn", 123456789, 2, "1,234,567.89" },
+        UDec64LocNumberingTC{ "en", "unknown", 123456789, 2, "1,234,567.89" },
+        UDec64LocNumberingTC{ "th", "thai", 123456789, 2, "๑,๒๓๔,๕๖๗.๘๙" },
+        UDec64LocNumberingTC{ "lo", "laoo", 123456789, 2, "໑.໒໓໔.໕໖໗,໘໙" },
+        UDec64LocNumberingTC{ "km", "khmr", 123456789, 2, "១.២៣៤.៥៦៧,៨៩" },
+        UDec64LocNumberingTC{ "dz", "", 123456789, 2, "༡༢,༣༤,༥༦༧.༨༩" },
+        UDec64LocNumberingTC{ "ta", "tamldec", 123456789, 2, "௧௨,௩௪,௫௬௭.௮௯" },
+        UDec64LocNumberingTC{ "te", "telu", 123456789, 2, "౧,౨౩౪,౫౬౭.౮౯" },
+        UDec64LocNumberingTC{ "kn", "knda", 123456789, 2, "೧,೨೩೪,೫೬೭.೮೯" },
+        UDec64LocNumberingTC{ "ml", "mlym", 123456789, 2, "൧൨,൩൪,൫൬൭.൮൯" },
+        UDec64LocNumberingTC{ "gu", "gujr", 123456789, 2, "૧૨,૩૪,૫૬૭.૮૯" },
+        UDec64LocNumberingTC{ "pa", "guru", 123456789, 2, "੧੨,੩੪,੫੬੭.੮੯" },
+        UDec64LocNumberingTC{ "en", "orya", 123456789, 2, "୧,୨୩୪,୫୬୭.୮୯" },
+        UDec64LocNumberingTC{ "ja", "fullwide", 123456789, 2, "１,２３４,５６７.８９" },
+        UDec64LocNumberingTC{ "mn", "mong", 123456789, 2, "᠑,᠒᠓᠔,᠕᠖᠗.᠘᠙" },
+        UDec64LocNumberingTC{ "en", "nkoo", 123456789, 2, "߁,߂߃߄,߅߆߇.߈߉" },
+        UDec64LocNumberingTC{ "en", "adlm", 123456789, 2, "𞥑,𞥒𞥓𞥔,𞥕𞥖𞥗.𞥘𞥙" },
+        UDec64LocNumberingTC{ "zh", "hanidec", 102030405, 2, "一,〇二〇,三〇四.〇五" },
+        UDec64LocNumberingTC{ "ar", "latn", 123456789, 2, "1,234,567.89" },
+        // option overrides -u-nu- extension
+        UDec64LocNumberingTC{ "ar-u-nu-latn", "arab", 123456789, 2, "١٬٢٣٤٬٥٦٧٫٨٩" },
+        // native numbering system
+        UDec64LocNumberingTC{ "th-TH", "native", 123456789, 2, "๑,๒๓๔,๕๖๗.๘๙" },
+        UDec64LocNumberingTC{ "th-TH-u-nu-native", "", 123456789, 2, "๑,๒๓๔,๕๖๗.๘๙" },
+        UDec64LocNumberingTC{ "hi", "native", 123456789, 2, "१२,३४,५६७.८९" },
+        UDec64LocNumberingTC{ "ar", "native", 123456789, 2, "١٬٢٣٤٬٥٦٧٫٨٩" },
+        UDec64LocNumberingTC{ "en", "native", 123456789, 2, "1,234,567.89" },
+    }
+    for i, tc := range testCases {
+        opts := FormatOptions{ NumberingSystem: tc.numberingSystem }
+        result := tc.a.LocaleFormatOpts(tc.lang, tc.precision, opts)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%s,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.numberingSystem, tc.precision,
+                     tc.expected, result)
+        }
+        resultBytes := tc.a.LocaleFormatOptsBytes(tc.lang, tc.precision, opts)
+        if tc.expected!=string(resultBytes) {
+            t.Errorf("Result mismatch: %d: fmtBytes(%v,%s,%s,%v)->%v!=%v",
+                     i, tc.a, tc.lang, tc.numberingSystem, tc.precision,
+                     tc.expected, string(resultBytes))
+        }
+        if tc.numberingSystem=="unknown" { continue }
+        parsed, err := LocaleParseUDec64Numbering(tc.lang, tc.numberingSystem,
+                                                  tc.expected, tc.precision, false)
+        if tc.a!=parsed || err!=nil {
+            t.Errorf("Result mismatch: %d: parse(%s,%s,%v)->%v,%v!=%v,%v",
+                     i, tc.lang, tc.numberingSystem, tc.expected, tc.a, nil,
+                     parsed, err)
+        }
+        parsed, err = LocaleParseUDec64NumberingBytes(tc.lang, tc.numberingSystem,
+                                []byte(tc.expected), tc.precision, false)
+        if tc.a!=parsed || err!=nil {
+            t.Errorf("Result mismatch: %d: parseBytes(%s,%s,%v)->%v,%v!=%v,%v",
+                     i, tc.lang, tc.numberingSystem, tc.expected, tc.a, nil,
+                     parsed, err)
+        }
+    }
+}
+
+func TestIsNumberingSystem(t *testing.T) {
+    for _, ns := range []string{ "latn", "arab", "thai", "tibt", "mong", "nkoo",
+                "adlm", "hanidec", "fullwide", "mathbold" } {
+        if !IsNumberingSystem(ns) {
+            t.Errorf("Result mismatch: %s: not numbering system", ns)
+        }
+    }
+    for _, ns := range []string{ "", "native", "roman", "hans" } {
+        if IsNumberingSystem(ns) {
+            t.Errorf("Result mismatch: %s: numbering system", ns)
+        }
+    }
+}
+
+type RegisterLocaleTC struct {
+    tag string
+    l LocFmt
+    expError error
+}
+
+func TestRegisterLocaleCheck(t *testing.T) {
+    testCases := []RegisterLocaleTC {
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '\'', '\'', false,
+                []rune("0123456789") }, nil },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '\'', '\'', false,
+                []rune("012345678") }, ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '\'', '\'', false,
+                []rune("01234567890") }, ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '\'', '\'', false,
+                []rune("0123456788") }, ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '\'', '\'', false, nil },
+                ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '5', '\'', '\'', false,
+                []rune("0123456789") }, ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', '.', '.', false,
+                []rune("0123456789") }, ErrLocFmt },
+        RegisterLocaleTC{ "en-US", LocFmt{ '.', ',', '1', false,
+                []rune("0123456789") }, ErrLocFmt },
+        RegisterLocaleTC{ "en US", LocFmt{ '.', '\'', '\'', false,
+                []rune("0123456789") }, ErrLanguageTag },
+        RegisterLocaleTC{ "en-u-nu-house", LocFmt{ '.', '\'', '\'', false,
+                []rune("0123456789") }, ErrLanguageTag },
+    }
+    for i, tc := range testCases {
+        err := RegisterLocale(tc.tag, tc.l)
+        if tc.expError!=err {
+            t.Errorf("Result mismatch: %d: register(%v,%v)->%v!=%v",
+                     i, tc.tag, tc.l, tc.expError, err)
+        }
+        if UnregisterLocale(tc.tag)!=(err==nil) {
+            t.Errorf("Result mismatch: %d: unregister(%v)", i, tc.tag)
+        }
+    }
+}
+
+func TestRegisterLocale(t *testing.T) {
+    var a UDec64 = 123456789
+    digits := []rune("0123456789")
+    if err := RegisterLocale("en", LocFmt{ '.', '\'', '\'', false, digits });
+            err!=nil {
+        t.Fatalf("register: %v", err)
+    }
+    // registered digits are copied
+    digits[0] = 'x'
+    for i, lang := range []string{ "en", "EN_us", "en-GB", "en-u-nu-latn" } {
+        if result := a.LocaleFormat(lang, 2, false, false); result!="1'234'567.89" {
+            t.Errorf("Result mismatch: %d: fmt(%s)->%v!=%v", i, lang, "1'234'567.89",
+                     result)
+        }
+    }
+    if result := a.LocaleFormat("en-u-nu-thai", 2, false, false);
+            result!="๑'๒๓๔'๕๖๗.๘๙" {
+        t.Errorf("Result mismatch: fmt(en-u-nu-thai)->%v!=%v", "๑'๒๓๔'๕๖๗.๘๙", result)
+    }
+    // more specific built-in locale is not overridden
+    if result := a.LocaleFormat("en-IN", 2, false, false); result!="12,34,567.89" {
+        t.Errorf("Result mismatch: fmt(en-IN)->%v!=%v", "12,34,567.89", result)
+    }
+    if err := RegisterLocale("en-u-nu-thai", LocFmt{ ',', ' ', ' ', false,
+                []rune("๐๑๒๓๔๕๖๗๘๙") }); err!=nil {
+        t.Fatalf("register: %v", err)
+    }
+    if result := a.LocaleFormat("en-u-nu-thai", 2, false, false);
+            result!="๑ ๒๓๔ ๕๖๗,๘๙" {
+        t.Errorf("Result mismatch: fmt(en-u-nu-thai)->%v!=%v", "๑ ๒๓๔ ๕๖๗,๘๙", result)
+    }
+    if v, err := LocaleParseUDec64("en", "1'234'567.89", 2, false); v!=a || err!=nil {
+        t.Errorf("Result mismatch: parse(en)->%v,%v!=%v,%v", a, nil, v, err)
+    }
+    if !UnregisterLocale("en-u-nu-thai") || !UnregisterLocale("en") {
+        t.Errorf("Result mismatch: unregister")
+    }
+    if UnregisterLocale("en") {
+        t.Errorf("Result mismatch: unregister twice")
+    }
+    if result := a.LocaleFormat("en", 2, false, false); result!="1,234,567.89" {
+        t.Errorf("Result mismatch: fmt(en)->%v!=%v", "1,234,567.89", result)
+    }
+}
+
+func TestRegisterLocaleConcurrent(t *testing.T) {
+    var wg sync.WaitGroup
+    for g := 0; g < 4; g++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := 0; i < 1000; i++ {
+                result := UDec64(123456789).LocaleFormat("de", 2, false, false)
+                if result!="1.234.567,89" && result!="1'234'567,89" {
+                    t.Errorf("Result mismatch: %d: fmt->%v", i, result)
+                    return
+                }
+            }
+        }()
+    }
+    for i := 0; i < 1000; i++ {
+        RegisterLocale("de", LocFmt{ ',', '\'', '\'', false, latnDigits })
+        UnregisterLocale("de")
+    }
+    wg.Wait()
+}
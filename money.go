@@ -0,0 +1,249 @@
+/*
+ * money.go - amount of money in currency
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "math/bits"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+)
+
+// operation on amounts in different currencies
+var ErrCurrencyMismatch = errors.New("godec64: currency mismatch")
+
+// amount of money in ISO 4217 currency. Amount is held as magnitude with
+// precision equal to minor unit of currency and sign. Zero value is invalid
+// (has no currency). Money values can be compared by ==
+type Money struct {
+    amount UDec64
+    negative bool
+    precision uint8
+    currency string
+}
+
+// create money from amount with precision. Amount is rounded to minor unit
+// of currency. Returns ErrCurrency if currency is unknown or strconv.ErrRange
+// if amount is too big
+func NewMoney(amount UDec64, precision uint, currency string,
+              mode RoundingMode) (Money, error) {
+    c, ok := iso4217Currencies[currency]
+    if !ok { return Money{}, ErrCurrency }
+    v, err := coefToUDec64(uint64(amount), -int(precision), c.MinorUnits, mode)
+    if err!=nil { return Money{}, err }
+    return Money{ v, false, uint8(c.MinorUnits), c.Code }, nil
+}
+
+// create money from number of minor units (for example cents)
+func NewMoneyMinor(units UDec64, currency string) (Money, error) {
+    c, ok := iso4217Currencies[currency]
+    if !ok { return Money{}, ErrCurrency }
+    return Money{ units, false, uint8(c.MinorUnits), c.Code }, nil
+}
+
+// get amount (magnitude) with precision returned by Precision
+func (m Money) Amount() UDec64 {
+    return m.amount
+}
+
+// get precision of amount (minor unit of currency)
+func (m Money) Precision() uint {
+    return uint(m.precision)
+}
+
+// get ISO 4217 currency code
+func (m Money) Currency() string {
+    return m.currency
+}
+
+// returns true if amount is negative
+func (m Money) IsNegative() bool {
+    return m.negative
+}
+
+// returns true if amount is zero
+func (m Money) IsZero() bool {
+    return m.amount==0
+}
+
+// return money with negated amount
+func (m Money) Neg() Money {
+    if m.amount!=0 {
+        m.negative = !m.negative
+    }
+    return m
+}
+
+// add amounts in same currency. Returns ErrCurrencyMismatch if currencies
+// differ or strconv.ErrRange if result is too big
+func (m Money) Add(b Money) (Money, error) {
+    if m.currency!=b.currency { return Money{}, ErrCurrencyMismatch }
+    if m.negative==b.negative {
+        s, carry := bits.Add64(uint64(m.amount), uint64(b.amount), 0)
+        if carry!=0 { return Money{}, strconv.ErrRange }
+        m.amount = UDec64(s)
+        return m, nil
+    }
+    if m.amount >= b.amount {
+        m.amount -= b.amount
+    } else {
+        m.amount = b.amount - m.amount
+        m.negative = b.negative
+    }
+    if m.amount==0 {
+        m.negative = false
+    }
+    return m, nil
+}
+
+// subtract amounts in same currency. Returns ErrCurrencyMismatch if currencies
+// differ or strconv.ErrRange if result is too big
+func (m Money) Sub(b Money) (Money, error) {
+    return m.Add(b.Neg())
+}
+
+// multiply amount by decimal factor with precision and sign (for example
+// quantity or rate). Result is rounded to minor unit of currency by rounding
+// mode (applied to magnitude). Returns strconv.ErrRange if result is too big
+func (m Money) Mul(factor UDec64, precision uint, negative bool,
+                   mode RoundingMode) (Money, error) {
+    hi, lo := bits.Mul64(uint64(m.amount), uint64(factor))
+    v, ok := div128Pow10(hi, lo, precision, mode)
+    if !ok { return Money{}, strconv.ErrRange }
+    m.amount = UDec64(v)
+    m.negative = m.negative!=negative && v!=0
+    return m, nil
+}
+
+// compare amounts in same currency, returns -1 if m<b, 0 if m==b, 1 if m>b.
+// Returns ErrCurrencyMismatch if currencies differ
+func (m Money) Cmp(b Money) (int, error) {
+    if m.currency!=b.currency { return 0, ErrCurrencyMismatch }
+    switch {
+    case m.negative && !b.negative:
+        return -1, nil
+    case !m.negative && b.negative:
+        return 1, nil
+    }
+    r := 0
+    if m.amount < b.amount {
+        r = -1
+    } else if m.amount > b.amount {
+        r = 1
+    }
+    if m.negative { r = -r }
+    return r, nil
+}
+
+// format money in locale with currency symbol (see FormatCurrency)
+func (m Money) LocaleFormat(lang string) string {
+    return m.LocaleFormatOpts(lang, CurrencyOptions{})
+}
+
+// format money in locale with options. Negative field of options is ignored
+func (m Money) LocaleFormatOpts(lang string, opts CurrencyOptions) string {
+    opts.Negative = m.negative
+    dst, _ := m.amount.AppendCurrency(nil, uint(m.precision), m.currency, lang, opts)
+    return string(dst)
+}
+
+// format money as amount and currency code, for example "-1234.50 USD"
+func (m Money) String() string {
+    var buf [48]byte
+    dst := buf[:0]
+    if m.negative {
+        dst = append(dst, '-')
+    }
+    dst = append(dst, m.amount.FormatBytes(uint(m.precision), false)...)
+    dst = append(dst, ' ')
+    dst = append(dst, m.currency...)
+    return string(dst)
+}
+
+// parse money from amount and currency code separated by space (code can be
+// before or after amount), for example "-1234.50 USD" or "USD 12". Amount can
+// have sign. Digits after minor unit of currency and exponent are not allowed
+func ParseMoney(str string) (Money, error) {
+    amountStr, code := str, ""
+    if i := strings.IndexByte(str, ' '); i >= 0 {
+        amountStr, code = str[:i], str[i+1:]
+        if len(amountStr)==3 && len(code)!=0 && (code[0]<'A' || code[0]>'Z') {
+            amountStr, code = code, amountStr
+        }
+    }
+    c, ok := iso4217Currencies[code]
+    if !ok { return Money{}, ErrCurrency }
+    negative := false
+    if len(amountStr)!=0 && (amountStr[0]=='-' || amountStr[0]=='+') {
+        negative = amountStr[0]=='-'
+        amountStr = amountStr[1:]
+    }
+    if len(amountStr)==0 || amountStr[0]<'0' || amountStr[0]>'9' {
+        return Money{}, strconv.ErrSyntax
+    }
+    if strings.IndexAny(amountStr, "eE") >= 0 {
+        return Money{}, strconv.ErrSyntax
+    }
+    if i := strings.IndexByte(amountStr, '.'); i >= 0 &&
+            len(amountStr)-i-1 > int(c.MinorUnits) {
+        return Money{}, strconv.ErrSyntax
+    }
+    v, err := ParseUDec64(amountStr, c.MinorUnits, false)
+    if err!=nil { return Money{}, err }
+    return Money{ v, negative && v!=0, uint8(c.MinorUnits), c.Code }, nil
+}
+
+// parse money formatted in locale (by LocaleFormat) in currency. Currency
+// symbol or code and spaces are skipped, sign '-' before or after number
+// is accepted. Digits after minor unit of currency are rounded half up
+// if rounding is set, otherwise truncated
+func LocaleParseMoney(lang, str, currency string, rounding bool) (Money, error) {
+    c, ok := iso4217Currencies[currency]
+    if !ok { return Money{}, ErrCurrency }
+    _, symbol, _, err := findCurrencyFormat(lang, &c)
+    if err!=nil { return Money{}, err }
+    // remove currency symbol or code
+    if i := strings.Index(str, symbol); i >= 0 {
+        str = str[:i] + str[i+len(symbol):]
+    } else if i := strings.Index(str, c.Code); i >= 0 {
+        str = str[:i] + str[i+len(c.Code):]
+    }
+    // remove spaces and sign
+    negative := false
+    var numBuf [64]byte
+    num := numBuf[:0]
+    for _, r := range str {
+        switch r {
+        case '-', '\u2212':
+            if negative { return Money{}, strconv.ErrSyntax }
+            negative = true
+        case ' ', '\u00a0', '\u202f':
+        default:
+            num = utf8.AppendRune(num, r)
+        }
+    }
+    v, err := localeParseUDec64Bytes(GetLocFmt(lang), num, c.MinorUnits, rounding)
+    if err!=nil { return Money{}, err }
+    return Money{ v, negative && v!=0, uint8(c.MinorUnits), c.Code }, nil
+}
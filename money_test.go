@@ -0,0 +1,293 @@
+/*
+ * money_test.go - amount of money in currency tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "strconv"
+    "testing"
+)
+
+func mustParseMoney(t *testing.T, str string) Money {
+    m, err := ParseMoney(str)
+    if err!=nil {
+        t.Fatalf("parse(%v): %v", str, err)
+    }
+    return m
+}
+
+type NewMoneyTC struct {
+    amount UDec64
+    precision uint
+    currency string
+    mode RoundingMode
+    expected string
+    expError error
+}
+
+func TestNewMoney(t *testing.T) {
+    testCases := []NewMoneyTC {
+        NewMoneyTC{ 12345, 2, "USD", RoundHalfEven, "123.45 USD", nil },
+        NewMoneyTC{ 12345, 3, "USD", RoundHalfEven, "12.34 USD", nil },
+        NewMoneyTC{ 12355, 3, "USD", RoundHalfEven, "12.36 USD", nil },
+        NewMoneyTC{ 12345, 3, "USD", RoundUp, "12.35 USD", nil },
+        NewMoneyTC{ 12345, 0, "USD", RoundDown, "12345.00 USD", nil },
+        NewMoneyTC{ 12345, 2, "JPY", RoundHalfUp, "123 JPY", nil },
+        NewMoneyTC{ 12345, 2, "BHD", RoundHalfUp, "123.450 BHD", nil },
+        NewMoneyTC{ 12345, 2, "CLF", RoundHalfUp, "123.4500 CLF", nil },
+        NewMoneyTC{ 0, 2, "EUR", RoundHalfUp, "0.00 EUR", nil },
+        NewMoneyTC{ 18446744073709551615, 2, "CLF", RoundHalfUp, "", strconv.ErrRange },
+        NewMoneyTC{ 12345, 2, "XYZ", RoundHalfUp, "", ErrCurrency },
+        NewMoneyTC{ 12345, 2, "usd", RoundHalfUp, "", ErrCurrency },
+    }
+    for i, tc := range testCases {
+        m, err := NewMoney(tc.amount, tc.precision, tc.currency, tc.mode)
+        result := ""
+        if err==nil {
+            result = m.String()
+        }
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: new(%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.amount, tc.precision, tc.currency, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+    m, err := NewMoneyMinor(1999, "EUR")
+    if m.String()!="19.99 EUR" || m.Amount()!=1999 || m.Precision()!=2 ||
+            m.Currency()!="EUR" || err!=nil {
+        t.Errorf("Result mismatch: newMinor->%v,%v", m, err)
+    }
+}
+
+type MoneyArithTC struct {
+    a, b string
+    expected string
+    expError error
+}
+
+func TestMoneyAddSub(t *testing.T) {
+    testCases := []MoneyArithTC {
+        MoneyArithTC{ "1.50 USD", "2.75 USD", "4.25 USD", nil },
+        MoneyArithTC{ "1.50 USD", "-2.75 USD", "-1.25 USD", nil },
+        MoneyArithTC{ "-1.50 USD", "2.75 USD", "1.25 USD", nil },
+        MoneyArithTC{ "-1.50 USD", "-2.75 USD", "-4.25 USD", nil },
+        MoneyArithTC{ "-1.50 USD", "1.50 USD", "0.00 USD", nil },
+        MoneyArithTC{ "100 JPY", "23 JPY", "123 JPY", nil },
+        MoneyArithTC{ "184467440737095516.15 USD", "0.01 USD", "", strconv.ErrRange },
+        MoneyArithTC{ "1.50 USD", "1.50 EUR", "", ErrCurrencyMismatch },
+        MoneyArithTC{ "1.500 BHD", "1.500 KWD", "", ErrCurrencyMismatch },
+    }
+    for i, tc := range testCases {
+        a, b := mustParseMoney(t, tc.a), mustParseMoney(t, tc.b)
+        m, err := a.Add(b)
+        result := ""
+        if err==nil {
+            result = m.String()
+        }
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: add(%v,%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.b, tc.expected, tc.expError, result, err)
+        }
+        // a-(-b) == a+b
+        m, err = a.Sub(b.Neg())
+        result = ""
+        if err==nil {
+            result = m.String()
+        }
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: sub(%v,-%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.b, tc.expected, tc.expError, result, err)
+        }
+    }
+    // zero is never negative
+    zero, _ := mustParseMoney(t, "-1.50 USD").Add(mustParseMoney(t, "1.50 USD"))
+    if zero!=mustParseMoney(t, "0.00 USD") || zero.IsNegative() || !zero.IsZero() {
+        t.Errorf("Result mismatch: zero->%v", zero)
+    }
+}
+
+type MoneyMulTC struct {
+    a string
+    factor UDec64
+    precision uint
+    negative bool
+    mode RoundingMode
+    expected string
+    expError error
+}
+
+func TestMoneyMul(t *testing.T) {
+    testCases := []MoneyMulTC {
+        MoneyMulTC{ "19.99 USD", 3, 0, false, RoundHalfEven, "59.97 USD", nil },
+        MoneyMulTC{ "19.99 USD", 3, 0, true, RoundHalfEven, "-59.97 USD", nil },
+        MoneyMulTC{ "-19.99 USD", 3, 0, true, RoundHalfEven, "59.97 USD", nil },
+        MoneyMulTC{ "100.00 EUR", 23, 2, false, RoundHalfEven, "23.00 EUR", nil },
+        MoneyMulTC{ "10.05 EUR", 5, 1, false, RoundHalfEven, "5.02 EUR", nil },
+        MoneyMulTC{ "10.05 EUR", 5, 1, false, RoundHalfUp, "5.03 EUR", nil },
+        MoneyMulTC{ "-10.05 EUR", 5, 1, false, RoundDown, "-5.02 EUR", nil },
+        MoneyMulTC{ "-10.05 EUR", 5, 1, false, RoundUp, "-5.03 EUR", nil },
+        MoneyMulTC{ "1000 JPY", 1234567, 7, false, RoundHalfEven, "123 JPY", nil },
+        MoneyMulTC{ "1.00 USD", 0, 0, true, RoundHalfEven, "0.00 USD", nil },
+        MoneyMulTC{ "100000000000000000.00 USD", 2, 0, false, RoundHalfEven,
+                "", strconv.ErrRange },
+    }
+    for i, tc := range testCases {
+        m, err := mustParseMoney(t, tc.a).Mul(tc.factor, tc.precision, tc.negative,
+                                              tc.mode)
+        result := ""
+        if err==nil {
+            result = m.String()
+        }
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: mul(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.factor, tc.precision, tc.negative, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+func TestMoneyCmp(t *testing.T) {
+    testCases := []MoneyArithTC {
+        MoneyArithTC{ "1.50 USD", "2.75 USD", "-1", nil },
+        MoneyArithTC{ "2.75 USD", "1.50 USD", "1", nil },
+        MoneyArithTC{ "2.75 USD", "2.75 USD", "0", nil },
+        MoneyArithTC{ "-2.75 USD", "1.50 USD", "-1", nil },
+        MoneyArithTC{ "1.50 USD", "-2.75 USD", "1", nil },
+        MoneyArithTC{ "-2.75 USD", "-1.50 USD", "-1", nil },
+        MoneyArithTC{ "-0.00 USD", "0.00 USD", "0", nil },
+        MoneyArithTC{ "1.50 USD", "1.50 EUR", "0", ErrCurrencyMismatch },
+    }
+    for i, tc := range testCases {
+        r, err := mustParseMoney(t, tc.a).Cmp(mustParseMoney(t, tc.b))
+        if tc.expected!=strconv.Itoa(r) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: cmp(%v,%v)->%v,%v!=%v,%v",
+                     i, tc.a, tc.b, tc.expected, tc.expError, r, err)
+        }
+    }
+}
+
+type ParseMoneyTC struct {
+    str string
+    expected string
+    expError error
+}
+
+func TestParseMoney(t *testing.T) {
+    testCases := []ParseMoneyTC {
+        ParseMoneyTC{ "1234.5 USD", "1234.50 USD", nil },
+        ParseMoneyTC{ "USD 1234.5", "1234.50 USD", nil },
+        ParseMoneyTC{ "-1234.56 EUR", "-1234.56 EUR", nil },
+        ParseMoneyTC{ "EUR -1234.56", "-1234.56 EUR", nil },
+        ParseMoneyTC{ "+12 JPY", "12 JPY", nil },
+        ParseMoneyTC{ "-0 JPY", "0 JPY", nil },
+        ParseMoneyTC{ "1.2345 CLF", "1.2345 CLF", nil },
+        ParseMoneyTC{ "1.234 USD", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "1.5 JPY", "", strconv.ErrSyntax },
+        // exponent form is not allowed
+        ParseMoneyTC{ "1e-3 USD", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "1.234e1 USD", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "USD 12E0", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "1.50", "", ErrCurrency },
+        ParseMoneyTC{ "1.50 usd", "", ErrCurrency },
+        ParseMoneyTC{ "1.50  USD", "", ErrCurrency },
+        ParseMoneyTC{ "- USD", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "x1 USD", "", strconv.ErrSyntax },
+        ParseMoneyTC{ "1x USD", "", strconv.ErrSyntax },
+    }
+    for i, tc := range testCases {
+        m, err := ParseMoney(tc.str)
+        result := ""
+        if err==nil {
+            result = m.String()
+        }
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: parse(%v)->%v,%v!=%v,%v",
+                     i, tc.str, tc.expected, tc.expError, result, err)
+        }
+    }
+}
+
+type MoneyLocaleTC struct {
+    money string
+    lang string
+    expected string
+}
+
+func TestMoneyLocaleFormat(t *testing.T) {
+    testCases := []MoneyLocaleTC {
+        MoneyLocaleTC{ "1234.56 USD", "en", "$1,234.56" },
+        MoneyLocaleTC{ "-1234.56 USD", "en", "-$1,234.56" },
+        MoneyLocaleTC{ "-1234.56 EUR", "de", "-1.234,56\u00a0€" },
+        MoneyLocaleTC{ "-1234.56 EUR", "nl", "€\u00a0-1.234,56" },
+        MoneyLocaleTC{ "-1234.56 CHF", "de-CH", "CHF-1’234.56" },
+        MoneyLocaleTC{ "1234567 JPY", "ja", "￥1,234,567" },
+        MoneyLocaleTC{ "-1234.500 BHD", "en", "-BHD\u00a01,234.500" },
+        MoneyLocaleTC{ "1234.56 PLN", "pl", "1\u00a0234,56\u00a0zł" },
+        MoneyLocaleTC{ "1234.56 EGP", "ar", "١٬٢٣٤٫٥٦\u00a0EGP" },
+        MoneyLocaleTC{ "1234.56 INR", "hi", "₹1,234.56" },
+    }
+    for i, tc := range testCases {
+        m := mustParseMoney(t, tc.money)
+        result := m.LocaleFormat(tc.lang)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: fmt(%v,%v)->%q!=%q",
+                     i, tc.money, tc.lang, tc.expected, result)
+        }
+        parsed, err := LocaleParseMoney(tc.lang, result, m.Currency(), false)
+        if m!=parsed || err!=nil {
+            t.Errorf("Result mismatch: %d: parse(%v,%q)->%v,%v!=%v,%v",
+                     i, tc.lang, result, m, nil, parsed, err)
+        }
+        code := m.LocaleFormatOpts(tc.lang, CurrencyOptions{ Display: CurrencyCode })
+        parsed, err = LocaleParseMoney(tc.lang, code, m.Currency(), false)
+        if m!=parsed || err!=nil {
+            t.Errorf("Result mismatch: %d: parseCode(%v,%q)->%v,%v!=%v,%v",
+                     i, tc.lang, code, m, nil, parsed, err)
+        }
+    }
+}
+
+func TestLocaleParseMoney(t *testing.T) {
+    testCases := []MoneyLocaleTC {
+        MoneyLocaleTC{ "12.35 USD", "en", "$12.345" },
+        MoneyLocaleTC{ "-12.35 USD", "en", "$ 12.345-" },
+        MoneyLocaleTC{ "1234.50 EUR", "fr", "1 234,5 €" },
+        MoneyLocaleTC{ "1234.50 EUR", "fr", "1234,50" },
+        MoneyLocaleTC{ "-1235 JPY", "en", "−¥1,234.5" },
+    }
+    for i, tc := range testCases {
+        m := mustParseMoney(t, tc.money)
+        parsed, err := LocaleParseMoney(tc.lang, tc.expected, m.Currency(), true)
+        if m!=parsed || err!=nil {
+            t.Errorf("Result mismatch: %d: parse(%v,%q)->%v,%v!=%v,%v",
+                     i, tc.lang, tc.expected, m, nil, parsed, err)
+        }
+    }
+    invalid := []string{ "", "$", "--1", "1-2-", "1.2.3", "$1x" }
+    for i, str := range invalid {
+        if _, err := LocaleParseMoney("en", str, "USD", true); err!=strconv.ErrSyntax {
+            t.Errorf("Result mismatch: %d: parse(%q)->%v", i, str, err)
+        }
+    }
+    if _, err := LocaleParseMoney("en", "1", "ABC", true); err!=ErrCurrency {
+        t.Errorf("Result mismatch: parse(ABC)->%v", err)
+    }
+}
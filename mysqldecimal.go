@@ -0,0 +1,155 @@
+/*
+ * mysqldecimal.go - MySQL DECIMAL binary storage format
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "strconv"
+)
+
+// invalid DECIMAL(M,D) column type
+var ErrMySQLDecimalType = errors.New("godec64: invalid MySQL DECIMAL type")
+
+const (
+    mysqlDecimalMaxM = 65
+    mysqlDecimalMaxD = 30
+)
+
+// number of bytes needed to store given number of digits (lower than 9)
+var mysqlDig2Bytes = [10]int{ 0, 1, 1, 2, 2, 3, 3, 4, 4, 4 }
+
+// get number of bytes of DECIMAL(M,D) in binary form
+func mysqlDecimalSize(m, d uint) int {
+    intg, frac := m-d, d
+    return int(intg/9)*4 + mysqlDig2Bytes[intg%9] +
+            int(frac/9)*4 + mysqlDig2Bytes[frac%9]
+}
+
+func checkMySQLDecimalType(m, d uint) error {
+    if m==0 || m > mysqlDecimalMaxM || d > mysqlDecimalMaxD || d > m {
+        return ErrMySQLDecimalType
+    }
+    return nil
+}
+
+// get number of bytes of DECIMAL(M,D) in binary form
+func MySQLDecimalSize(m, d uint) (int, error) {
+    if err := checkMySQLDecimalType(m, d); err!=nil {
+        return 0, err
+    }
+    return mysqlDecimalSize(m, d), nil
+}
+
+// group sizes (in digits) in order of storage
+func mysqlDecimalGroups(m, d uint) []uint8 {
+    groups := make([]uint8, 0, 16)
+    intg, frac := m-d, d
+    if intg%9!=0 { groups = append(groups, uint8(intg%9)) }
+    for i:=uint(0); i < intg/9; i++ { groups = append(groups, 9) }
+    for i:=uint(0); i < frac/9; i++ { groups = append(groups, 9) }
+    if frac%9!=0 { groups = append(groups, uint8(frac%9)) }
+    return groups
+}
+
+// append MySQL DECIMAL(M,D) binary form to dst. Value is rounded to D digits
+// after comma if precision is greater than D. Returns strconv.ErrRange if
+// value does not fit in M-D digits of integer part
+func (a UDec64) AppendMySQLDecimal(dst []byte, precision uint, m, d uint,
+                                   mode RoundingMode) ([]byte, error) {
+    if err := checkMySQLDecimalType(m, d); err!=nil {
+        return dst, err
+    }
+    v := uint64(a)
+    if d < precision {
+        v = divPow10(v, precision-d, mode)
+        precision = d
+    }
+    var digits [mysqlDecimalMaxM]byte
+    if !fixedDigits(digits[:m], v, precision, int(m-d)) {
+        return dst, strconv.ErrRange
+    }
+    start := len(dst)
+    groups := mysqlDecimalGroups(m, d)
+    pos := 0
+    for _, g := range groups {
+        var gv uint32
+        for i:=0; i < int(g); i++ {
+            gv = gv*10 + uint32(digits[pos+i])
+        }
+        pos += int(g)
+        for k:=mysqlDig2Bytes[g]-1; k >= 0; k-- {
+            dst = append(dst, byte(gv>>(8*uint(k))))
+        }
+    }
+    dst[start] ^= 0x80 // positive sign
+    return dst, nil
+}
+
+// convert to MySQL DECIMAL(M,D) binary form
+func (a UDec64) ToMySQLDecimal(precision uint, m, d uint,
+                               mode RoundingMode) ([]byte, error) {
+    return a.AppendMySQLDecimal(nil, precision, m, d, mode)
+}
+
+// convert MySQL DECIMAL(M,D) binary form to UDec64 with precision and
+// rounding mode. Length of b must be equal to size of DECIMAL(M,D)
+func MySQLDecimalToUDec64(b []byte, m, d uint, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if err := checkMySQLDecimalType(m, d); err!=nil {
+        return 0, err
+    }
+    if len(b)!=mysqlDecimalSize(m, d) {
+        return 0, strconv.ErrSyntax
+    }
+    var mask byte
+    if b[0]&0x80==0 {
+        mask = 0xff // negative: all bits inverted
+    }
+    var digits [mysqlDecimalMaxM]byte
+    groups := mysqlDecimalGroups(m, d)
+    pos, bpos := 0, 0
+    zero := true
+    for _, g := range groups {
+        var gv uint32
+        for k:=0; k < mysqlDig2Bytes[g]; k++ {
+            c := b[bpos]^mask
+            if bpos==0 { c ^= 0x80 }
+            gv = gv<<8 | uint32(c)
+            bpos++
+        }
+        if gv >= uint32(uint64_powers[g]) {
+            return 0, strconv.ErrSyntax
+        }
+        if gv!=0 { zero = false }
+        for i:=int(g)-1; i >= 0; i-- {
+            digits[pos+i] = byte(gv%10)
+            gv /= 10
+        }
+        pos += int(g)
+    }
+    if zero { return 0, nil }
+    if mask!=0 {
+        return 0, strconv.ErrRange // negative
+    }
+    return digitsToUDec64(digits[:m], -int(d), precision, mode)
+}
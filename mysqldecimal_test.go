@@ -0,0 +1,130 @@
+/*
+ * mysqldecimal_test.go - MySQL DECIMAL binary storage format tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToMySQLDecimalTC struct {
+    value UDec64
+    precision uint
+    m, d uint
+    mode RoundingMode
+    expected string
+    expError error
+}
+
+func TestUDec64ToMySQLDecimal(t *testing.T) {
+    testCases := []UDec64ToMySQLDecimalTC {
+        UDec64ToMySQLDecimalTC{ 0, 2, 10, 2, RoundDown, "8000000000", nil },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 10, 2, RoundDown, "8000007b2d", nil },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 5, 2, RoundDown, "807b2d", nil },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 4, 2, RoundDown, "", strconv.ErrRange },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 4, 1, RoundDown, "807b04", nil },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 4, 1, RoundHalfUp, "807b05", nil },
+        UDec64ToMySQLDecimalTC{ 12345, 2, 4, 1, RoundHalfEven, "807b04", nil },
+        UDec64ToMySQLDecimalTC{ 15, 1, 5, 0, RoundHalfUp, "800002", nil },
+        // example from MySQL documentation: DECIMAL(14,4) 1234567890.1234
+        UDec64ToMySQLDecimalTC{ 12345678901234, 4, 14, 4, RoundDown,
+                "810dfb38d204d2", nil },
+        UDec64ToMySQLDecimalTC{ 0xffffffffffffffff, 0, 20, 0, RoundDown,
+                "921aa0c6092a4ae5ff", nil },
+        UDec64ToMySQLDecimalTC{ 0xffffffffffffffff, 0, 19, 0, RoundDown,
+                "", strconv.ErrRange },
+        UDec64ToMySQLDecimalTC{ 0xffffffffffffffff, 9, 30, 9, RoundDown,
+                "8000000000121aa0c6092a4ae5ff", nil },
+        UDec64ToMySQLDecimalTC{ 1, 18, 30, 20, RoundDown,
+                "8000000000000000000000000100", nil },
+        // invalid types
+        UDec64ToMySQLDecimalTC{ 1, 0, 0, 0, RoundDown, "", ErrMySQLDecimalType },
+        UDec64ToMySQLDecimalTC{ 1, 0, 66, 0, RoundDown, "", ErrMySQLDecimalType },
+        UDec64ToMySQLDecimalTC{ 1, 0, 40, 31, RoundDown, "", ErrMySQLDecimalType },
+        UDec64ToMySQLDecimalTC{ 1, 0, 5, 6, RoundDown, "", ErrMySQLDecimalType },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.ToMySQLDecimal(tc.precision, tc.m, tc.d, tc.mode)
+        if tc.expected!=hex.EncodeToString(result) || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: tomysqldecimal(%v,%v,%v,%v,%v)->%v,%v!=%x,%v",
+                     i, tc.value, tc.precision, tc.m, tc.d, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+        if err!=nil { continue }
+        size, _ := MySQLDecimalSize(tc.m, tc.d)
+        if size!=len(result) {
+            t.Errorf("Size mismatch: %d: %v,%v->%v!=%v", i, tc.m, tc.d,
+                     len(result), size)
+        }
+    }
+}
+
+type MySQLDecimalToUDec64TC struct {
+    value string
+    m, d uint
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestMySQLDecimalToUDec64(t *testing.T) {
+    testCases := []MySQLDecimalToUDec64TC {
+        MySQLDecimalToUDec64TC{ "8000000000", 10, 2, 2, RoundDown, 0, nil },
+        // "-0"
+        MySQLDecimalToUDec64TC{ "7fffffffff", 10, 2, 2, RoundDown, 0, nil },
+        MySQLDecimalToUDec64TC{ "8000007b2d", 10, 2, 2, RoundDown, 12345, nil },
+        MySQLDecimalToUDec64TC{ "8000007b2d", 10, 2, 4, RoundDown, 1234500, nil },
+        MySQLDecimalToUDec64TC{ "8000007b2d", 10, 2, 1, RoundDown, 1234, nil },
+        MySQLDecimalToUDec64TC{ "8000007b2d", 10, 2, 1, RoundHalfUp, 1235, nil },
+        MySQLDecimalToUDec64TC{ "8000007b2d", 10, 2, 0, RoundUp, 124, nil },
+        MySQLDecimalToUDec64TC{ "810dfb38d204d2", 14, 4, 4, RoundDown,
+                12345678901234, nil },
+        MySQLDecimalToUDec64TC{ "921aa0c6092a4ae5ff", 20, 0, 0, RoundDown,
+                0xffffffffffffffff, nil },
+        MySQLDecimalToUDec64TC{ "8000000000121aa0c6092a4ae5ff", 30, 9, 9, RoundDown,
+                0xffffffffffffffff, nil },
+        MySQLDecimalToUDec64TC{ "8000000000000000000000000100", 30, 20, 18,
+                RoundDown, 1, nil },
+        // 99999999999999999999
+        MySQLDecimalToUDec64TC{ "e33b9ac9ff3b9ac9ff", 20, 0, 0, RoundDown,
+                0, strconv.ErrRange },
+        // -1234567890.1234
+        MySQLDecimalToUDec64TC{ "7ef204c72dfb2d", 14, 4, 4, RoundDown,
+                0, strconv.ErrRange },
+        // bad length, bad group value
+        MySQLDecimalToUDec64TC{ "80000000", 10, 2, 2, RoundDown, 0, strconv.ErrSyntax },
+        MySQLDecimalToUDec64TC{ "8000000064", 10, 2, 2, RoundDown, 0, strconv.ErrSyntax },
+        MySQLDecimalToUDec64TC{ "8000000000", 10, 11, 2, RoundDown,
+                0, ErrMySQLDecimalType },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := MySQLDecimalToUDec64(b, tc.m, tc.d, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: mysqldecimaltoudec64(%v,%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.m, tc.d, tc.precision, tc.mode,
+                     tc.expected, tc.expError, result, err)
+        }
+    }
+}
@@ -0,0 +1,87 @@
+/*
+ * oraclenumber.go - Oracle NUMBER binary format
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "strconv"
+)
+
+const (
+    oracleNumberZero = 0x80
+    oracleNumberExpBias = 0xc1 // exponent byte of positive number for 100^0
+    oracleNumberMaxDigits = 20 // maximal number of base-100 digits
+)
+
+// append Oracle NUMBER binary form (exponent byte and base-100 digits
+// stored as digit+1) to dst
+func (a UDec64) AppendOracleNumber(dst []byte, precision uint) []byte {
+    var dbuf [48]byte
+    digits, weight := groupedDigits(dbuf[:0], uint64(a), precision, 2)
+    if len(digits)==0 {
+        return append(dst, oracleNumberZero)
+    }
+    dst = append(dst, byte(oracleNumberExpBias+weight))
+    for i:=0; i < len(digits); i += 2 {
+        dst = append(dst, digits[i]*10+digits[i+1]+1)
+    }
+    return dst
+}
+
+// convert to Oracle NUMBER binary form
+func (a UDec64) ToOracleNumber(precision uint) []byte {
+    return a.AppendOracleNumber(nil, precision)
+}
+
+// convert Oracle NUMBER binary form to UDec64 with precision and rounding mode.
+// Negative values returns strconv.ErrRange
+func OracleNumberToUDec64(b []byte, precision uint,
+                          mode RoundingMode) (UDec64, error) {
+    if len(b)==0 || len(b) > 1+oracleNumberMaxDigits+1 {
+        return 0, strconv.ErrSyntax
+    }
+    switch {
+    case b[0]==oracleNumberZero:
+        if len(b)!=1 { return 0, strconv.ErrSyntax }
+        return 0, nil
+    case b[0]==0xff && len(b)==2 && b[1]==101:
+        return 0, ErrInfinity
+    case b[0]==0x00 && len(b)==1:
+        return 0, ErrInfinity // negative infinity
+    case b[0]&0x80==0:
+        return 0, strconv.ErrRange // negative
+    }
+    mant := b[1:]
+    if len(mant)==0 || len(mant) > oracleNumberMaxDigits {
+        return 0, strconv.ErrSyntax
+    }
+    var dbuf [2*oracleNumberMaxDigits]byte
+    digits := dbuf[:0]
+    for _, d := range mant {
+        if d < 1 || d > 100 {
+            return 0, strconv.ErrSyntax
+        }
+        digits = append(digits, (d-1)/10, (d-1)%10)
+    }
+    weight := int(b[0])-oracleNumberExpBias
+    return digitsToUDec64(digits, (weight-len(mant)+1)*2, precision, mode)
+}
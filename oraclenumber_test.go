@@ -0,0 +1,120 @@
+/*
+ * oraclenumber_test.go - Oracle NUMBER binary format tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToOracleNumberTC struct {
+    value UDec64
+    precision uint
+    expected string
+}
+
+func TestUDec64ToOracleNumber(t *testing.T) {
+    testCases := []UDec64ToOracleNumberTC {
+        UDec64ToOracleNumberTC{ 0, 0, "80" },
+        UDec64ToOracleNumberTC{ 0, 5, "80" },
+        UDec64ToOracleNumberTC{ 1, 0, "c102" },
+        UDec64ToOracleNumberTC{ 100, 2, "c102" },
+        UDec64ToOracleNumberTC{ 10, 0, "c10b" },
+        UDec64ToOracleNumberTC{ 100, 0, "c202" },
+        UDec64ToOracleNumberTC{ 1, 1, "c00b" },
+        UDec64ToOracleNumberTC{ 12345, 2, "c202182e" },
+        UDec64ToOracleNumberTC{ 12345, 1, "c20d2333" },
+        UDec64ToOracleNumberTC{ 5, 6, "be06" },
+        UDec64ToOracleNumberTC{ 0xffffffffffffffff, 0, "ca132d442d08260a381110" },
+        UDec64ToOracleNumberTC{ 0xffffffffffffffff, 18, "c1132d442d08260a381110" },
+    }
+    for i, tc := range testCases {
+        result := tc.value.ToOracleNumber(tc.precision)
+        if tc.expected!=hex.EncodeToString(result) {
+            t.Errorf("Result mismatch: %d: tooraclenumber(%v,%v)->%v!=%x",
+                     i, tc.value, tc.precision, tc.expected, result)
+        }
+        back, err := OracleNumberToUDec64(result, tc.precision, RoundDown)
+        if back!=tc.value || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%x->%v,%v",
+                     i, tc.value, tc.precision, result, back, err)
+        }
+    }
+}
+
+type OracleNumberToUDec64TC struct {
+    value string
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestOracleNumberToUDec64(t *testing.T) {
+    testCases := []OracleNumberToUDec64TC {
+        OracleNumberToUDec64TC{ "80", 2, RoundDown, 0, nil },
+        OracleNumberToUDec64TC{ "c102", 2, RoundDown, 100, nil },
+        // 123.45
+        OracleNumberToUDec64TC{ "c202182e", 2, RoundDown, 12345, nil },
+        OracleNumberToUDec64TC{ "c202182e", 5, RoundDown, 12345000, nil },
+        OracleNumberToUDec64TC{ "c202182e", 1, RoundDown, 1234, nil },
+        OracleNumberToUDec64TC{ "c202182e", 1, RoundHalfUp, 1235, nil },
+        OracleNumberToUDec64TC{ "c202182e", 1, RoundHalfEven, 1234, nil },
+        OracleNumberToUDec64TC{ "c202182e", 0, RoundUp, 124, nil },
+        // 0.000005
+        OracleNumberToUDec64TC{ "be06", 6, RoundDown, 5, nil },
+        OracleNumberToUDec64TC{ "be06", 5, RoundHalfUp, 1, nil },
+        OracleNumberToUDec64TC{ "be06", 5, RoundHalfDown, 0, nil },
+        // non-canonical trailing zero digit
+        OracleNumberToUDec64TC{ "c1020101", 2, RoundDown, 100, nil },
+        OracleNumberToUDec64TC{ "ca132d442d08260a381110", 0, RoundDown,
+                0xffffffffffffffff, nil },
+        OracleNumberToUDec64TC{ "ca132d442d08260a381110", 1, RoundDown,
+                0, strconv.ErrRange },
+        // 1E+20
+        OracleNumberToUDec64TC{ "cb02", 0, RoundDown, 0, strconv.ErrRange },
+        // -1, -123.45
+        OracleNumberToUDec64TC{ "3e6466", 0, RoundDown, 0, strconv.ErrRange },
+        OracleNumberToUDec64TC{ "3d644e3866", 0, RoundDown, 0, strconv.ErrRange },
+        // infinity, negative infinity
+        OracleNumberToUDec64TC{ "ff65", 0, RoundDown, 0, ErrInfinity },
+        OracleNumberToUDec64TC{ "00", 0, RoundDown, 0, ErrInfinity },
+        // malformed
+        OracleNumberToUDec64TC{ "", 0, RoundDown, 0, strconv.ErrSyntax },
+        OracleNumberToUDec64TC{ "c1", 0, RoundDown, 0, strconv.ErrSyntax },
+        OracleNumberToUDec64TC{ "8001", 0, RoundDown, 0, strconv.ErrSyntax },
+        OracleNumberToUDec64TC{ "c100", 0, RoundDown, 0, strconv.ErrSyntax },
+        OracleNumberToUDec64TC{ "c165", 0, RoundDown, 0, strconv.ErrSyntax },
+        OracleNumberToUDec64TC{ "c102020202020202020202020202020202020202020202",
+                0, RoundDown, 0, strconv.ErrSyntax },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := OracleNumberToUDec64(b, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: oraclenumbertoudec64(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
@@ -0,0 +1,310 @@
+/*
+ * packed.go - self-describing DEC64 packed format (Douglas Crockford's DEC64)
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "math/bits"
+    "strconv"
+)
+
+// packed decimal: 56-bit signed coefficient in high bits and 8-bit signed
+// exponent in low bits. Value is coefficient*10^exponent
+type PackedDec64 int64
+
+const (
+    packedNaNCoef = 0
+    packedNaNExp = -128
+)
+
+// canonical NaN (as in DEC64: coefficient 0 and exponent -128, 0x80)
+const PackedNaN PackedDec64 = packedNaNCoef<<8 | packedNaNExp&0xff
+
+const (
+    packedMaxCoef = 1<<55 - 1
+    packedMinExp = -127
+    packedMaxExp = 127
+)
+
+// get number of decimal digits of value
+func decDigits(a uint64) int {
+    n := 1
+    for ; n < len(uint64_powers) && a >= uint64_powers[n]; n++ { }
+    if n==len(uint64_powers) && a >= 10000000000000000000 {
+        n++
+    }
+    return n
+}
+
+// make packed decimal from 128-bit magnitude and exponent, coefficient
+// is rounded to fit in 56 bits
+func packCoef(neg bool, hi, lo uint64, exp int, mode RoundingMode) PackedDec64 {
+    if hi!=0 || lo > packedMaxCoef {
+        k := uint(1)
+        for {
+            v, ok := div128Pow10(hi, lo, k, RoundDown)
+            if ok && v <= packedMaxCoef {
+                v, _ = div128Pow10(hi, lo, k, mode)
+                if v <= packedMaxCoef {
+                    lo = v
+                    break
+                }
+            }
+            k++
+        }
+        exp += int(k)
+    }
+    c := lo
+    // fold-down if exponent is too big
+    for ; exp > packedMaxExp && c!=0 && c*10 <= packedMaxCoef; exp-- {
+        c *= 10
+    }
+    if exp > packedMaxExp {
+        if c==0 { return 0 }
+        return PackedNaN
+    }
+    if exp < packedMinExp {
+        c = divPow10(c, uint(packedMinExp-exp), mode)
+        exp = packedMinExp
+    }
+    if c==0 { return 0 }
+    sc := int64(c)
+    if neg { sc = -sc }
+    return PackedDec64(sc<<8 | int64(uint8(int8(exp))))
+}
+
+// make packed decimal from coefficient and exponent
+func NewPackedDec64(coef int64, exp int) PackedDec64 {
+    if coef < 0 {
+        return packCoef(true, 0, uint64(-coef), exp, RoundHalfUp)
+    }
+    return packCoef(false, 0, uint64(coef), exp, RoundHalfUp)
+}
+
+// convert UDec64 to packed decimal. Value is rounded if it does not fit
+// in 56-bit coefficient
+func PackUDec64(a UDec64, precision uint, mode RoundingMode) PackedDec64 {
+    return packCoef(false, 0, uint64(a), -int(precision), mode)
+}
+
+// get coefficient
+func (p PackedDec64) Coefficient() int64 {
+    return int64(p)>>8
+}
+
+// get exponent
+func (p PackedDec64) Exponent() int {
+    return int(int8(p))
+}
+
+// returns true if packed decimal is NaN
+func (p PackedDec64) IsNaN() bool {
+    return int8(p)==packedNaNExp
+}
+
+// returns true if packed decimal is zero
+func (p PackedDec64) IsZero() bool {
+    return !p.IsNaN() && p.Coefficient()==0
+}
+
+// get magnitude of coefficient and sign
+func (p PackedDec64) magnitude() (uint64, bool) {
+    c := p.Coefficient()
+    if c < 0 { return uint64(-c), true }
+    return uint64(c), false
+}
+
+// convert packed decimal to UDec64 with precision and rounding mode
+func (p PackedDec64) ToUDec64(precision uint, mode RoundingMode) (UDec64, error) {
+    if p.IsNaN() { return 0, ErrNaN }
+    c := p.Coefficient()
+    if c < 0 { return 0, strconv.ErrRange }
+    return coefToUDec64(uint64(c), p.Exponent(), precision, mode)
+}
+
+// negate
+func (p PackedDec64) Neg() PackedDec64 {
+    if p.IsNaN() { return p }
+    if p.Coefficient() < -packedMaxCoef {
+        // -2^55 has no positive counterpart, rescale it
+        return packCoef(false, 0, 1<<55, p.Exponent(), RoundHalfUp)
+    }
+    return PackedDec64((-p.Coefficient())<<8 | int64(uint8(int8(p.Exponent()))))
+}
+
+// add two packed decimals. Result is rounded half away from zero
+func (p PackedDec64) Add(b PackedDec64) PackedDec64 {
+    if p.IsNaN() || b.IsNaN() { return PackedNaN }
+    if b.Coefficient()==0 { return p }
+    if p.Coefficient()==0 { return b }
+    if p.Exponent() < b.Exponent() {
+        p, b = b, p
+    }
+    ma, nega := p.magnitude()
+    mb, negb := b.magnitude()
+    d := uint(p.Exponent()-b.Exponent())
+    // scale up greater exponent, scale down lower exponent
+    m := d
+    if m > 18 { m = 18 }
+    hi, lo := bits.Mul64(ma, uint64_powers[m])
+    sticky := false
+    if d > m {
+        if d-m >= uint(len(uint64_powers)) {
+            sticky = mb!=0
+            mb = 0
+        } else {
+            sticky = mb%uint64_powers[d-m]!=0
+            mb /= uint64_powers[d-m]
+        }
+    }
+    exp := p.Exponent()-int(m)
+    if nega==negb {
+        var carry uint64
+        lo, carry = bits.Add64(lo, mb, 0)
+        hi += carry
+        return packCoef(nega, hi, lo, exp, RoundHalfUp)
+    }
+    if hi!=0 || lo >= mb {
+        // if sticky then subtract also lost part of lower value
+        var borrow uint64
+        if sticky { mb++ }
+        lo, borrow = bits.Sub64(lo, mb, 0)
+        hi -= borrow
+        return packCoef(nega, hi, lo, exp, RoundHalfUp)
+    }
+    return packCoef(negb, 0, mb-lo, exp, RoundHalfUp)
+}
+
+// subtract two packed decimals. Result is rounded half away from zero
+func (p PackedDec64) Sub(b PackedDec64) PackedDec64 {
+    return p.Add(b.Neg())
+}
+
+// multiply two packed decimals. Result is rounded half away from zero
+func (p PackedDec64) Mul(b PackedDec64) PackedDec64 {
+    if p.IsNaN() || b.IsNaN() { return PackedNaN }
+    ma, nega := p.magnitude()
+    mb, negb := b.magnitude()
+    hi, lo := bits.Mul64(ma, mb)
+    return packCoef(nega!=negb, hi, lo, p.Exponent()+b.Exponent(), RoundHalfUp)
+}
+
+// divide two packed decimals. Result is rounded half away from zero.
+// Division by zero gives NaN
+func (p PackedDec64) Div(b PackedDec64) PackedDec64 {
+    if p.IsNaN() || b.IsNaN() { return PackedNaN }
+    ma, nega := p.magnitude()
+    mb, negb := b.magnitude()
+    if mb==0 { return PackedNaN }
+    if ma==0 { return 0 }
+    // scale dividend to get quotient in (10^17, 10^19)
+    s := uint(18 - decDigits(ma) + decDigits(mb))
+    s1 := s
+    if s1 > 18 { s1 = 18 }
+    hi, lo := bits.Mul64(ma, uint64_powers[s1])
+    if s > s1 {
+        h2, l2 := bits.Mul64(lo, uint64_powers[s-s1])
+        hi = hi*uint64_powers[s-s1] + h2
+        lo = l2
+    }
+    q, _ := bits.Div64(hi, lo, mb)
+    return packCoef(nega!=negb, 0, q, p.Exponent()-b.Exponent()-int(s), RoundHalfUp)
+}
+
+// compare two packed decimals: returns -1 if p<b, 0 if p==b, 1 if p>b.
+// NaN is lower than any other value
+func (p PackedDec64) Cmp(b PackedDec64) int {
+    if p.IsNaN() || b.IsNaN() {
+        if p.IsNaN() && b.IsNaN() { return 0 }
+        if p.IsNaN() { return -1 }
+        return 1
+    }
+    ma, nega := p.magnitude()
+    mb, negb := b.magnitude()
+    if ma==0 { nega = false }
+    if mb==0 { negb = false }
+    sign := 1
+    if nega { sign = -1 }
+    if nega!=negb {
+        return sign
+    }
+    if ma==0 || mb==0 {
+        if ma==mb { return 0 }
+        if ma==0 { return -1 }
+        return 1
+    }
+    // compare orders of magnitude
+    oa := decDigits(ma)+p.Exponent()
+    ob := decDigits(mb)+b.Exponent()
+    if oa!=ob {
+        if oa < ob { return -sign }
+        return sign
+    }
+    // difference between exponents is lower than 17
+    ea, eb := p.Exponent(), b.Exponent()
+    var ahi, alo, bhi, blo uint64
+    if ea >= eb {
+        ahi, alo = bits.Mul64(ma, uint64_powers[ea-eb])
+        bhi, blo = 0, mb
+    } else {
+        ahi, alo = 0, ma
+        bhi, blo = bits.Mul64(mb, uint64_powers[eb-ea])
+    }
+    if ahi==bhi && alo==blo { return 0 }
+    if ahi < bhi || (ahi==bhi && alo < blo) { return -sign }
+    return sign
+}
+
+// format packed decimal
+func (p PackedDec64) String() string {
+    if p.IsNaN() { return "NaN" }
+    m, neg := p.magnitude()
+    exp := p.Exponent()
+    var buf [160]byte
+    os := buf[:0]
+    if neg { os = append(os, '-') }
+    if exp >= 0 {
+        os = strconv.AppendUint(os, m, 10)
+        if m!=0 {
+            for ; exp > 0; exp-- {
+                os = append(os, '0')
+            }
+        }
+        return string(os)
+    }
+    var dbuf [24]byte
+    digits := strconv.AppendUint(dbuf[:0], m, 10)
+    slen := len(digits)
+    prec := -exp
+    if slen > prec {
+        os = append(os, digits[:slen-prec]...)
+        os = append(os, '.')
+        os = append(os, digits[slen-prec:]...)
+    } else {
+        os = append(os, '0', '.')
+        for i:=slen; i < prec; i++ {
+            os = append(os, '0')
+        }
+        os = append(os, digits...)
+    }
+    return string(os)
+}
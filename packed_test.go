@@ -0,0 +1,276 @@
+/*
+ * packed_test.go - self-describing DEC64 packed format tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "strconv"
+    "testing"
+)
+
+type PackUDec64TC struct {
+    value UDec64
+    precision uint
+    mode RoundingMode
+    expCoef int64
+    expExp int
+    expStr string
+}
+
+func TestPackUDec64(t *testing.T) {
+    testCases := []PackUDec64TC {
+        PackUDec64TC{ 0, 5, RoundDown, 0, 0, "0" },
+        PackUDec64TC{ 12345, 2, RoundDown, 12345, -2, "123.45" },
+        PackUDec64TC{ 5, 3, RoundDown, 5, -3, "0.005" },
+        PackUDec64TC{ 1200, 0, RoundDown, 1200, 0, "1200" },
+        PackUDec64TC{ 36028797018963967, 0, RoundDown, 36028797018963967, 0,
+                "36028797018963967" },
+        PackUDec64TC{ 36028797018963968, 0, RoundDown, 3602879701896396, 1,
+                "36028797018963960" },
+        PackUDec64TC{ 36028797018963968, 0, RoundHalfUp, 3602879701896397, 1,
+                "36028797018963970" },
+        PackUDec64TC{ 0xffffffffffffffff, 18, RoundHalfUp, 18446744073709552, -15,
+                "18.446744073709552" },
+        PackUDec64TC{ 0xffffffffffffffff, 18, RoundDown, 18446744073709551, -15,
+                "18.446744073709551" },
+    }
+    for i, tc := range testCases {
+        p := PackUDec64(tc.value, tc.precision, tc.mode)
+        if tc.expCoef!=p.Coefficient() || tc.expExp!=p.Exponent() {
+            t.Errorf("Result mismatch: %d: pack(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expCoef, tc.expExp,
+                     p.Coefficient(), p.Exponent())
+        }
+        if tc.expStr!=p.String() {
+            t.Errorf("Result mismatch: %d: string(%v)->%v!=%v",
+                     i, tc.value, tc.expStr, p.String())
+        }
+    }
+}
+
+type PackedToUDec64TC struct {
+    value PackedDec64
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestPackedDec64ToUDec64(t *testing.T) {
+    testCases := []PackedToUDec64TC {
+        PackedToUDec64TC{ NewPackedDec64(0, 0), 2, RoundDown, 0, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, -2), 2, RoundDown, 12345, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, -2), 4, RoundDown, 1234500, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, -2), 1, RoundDown, 1234, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, -2), 1, RoundHalfUp, 1235, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, 2), 0, RoundDown, 1234500, nil },
+        PackedToUDec64TC{ NewPackedDec64(12345, 20), 0, RoundDown, 0, strconv.ErrRange },
+        PackedToUDec64TC{ NewPackedDec64(-12345, 0), 0, RoundDown, 0, strconv.ErrRange },
+        PackedToUDec64TC{ PackedNaN, 0, RoundDown, 0, ErrNaN },
+    }
+    for i, tc := range testCases {
+        result, err := tc.value.ToUDec64(tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: toudec64(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
+
+type PackedArithTC struct {
+    a, b PackedDec64
+    expected string
+}
+
+func TestPackedDec64Add(t *testing.T) {
+    testCases := []PackedArithTC {
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(1, 0), "124.45" },
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(-1, 0), "122.45" },
+        PackedArithTC{ NewPackedDec64(1, 0), NewPackedDec64(-12345, -2), "-122.45" },
+        PackedArithTC{ NewPackedDec64(5, -1), NewPackedDec64(-5, -1), "0" },
+        PackedArithTC{ NewPackedDec64(0, 0), NewPackedDec64(-5, -1), "-0.5" },
+        PackedArithTC{ NewPackedDec64(-5, -1), NewPackedDec64(0, 3), "-0.5" },
+        PackedArithTC{ NewPackedDec64(36028797018963967, 0), NewPackedDec64(1, 0),
+                "36028797018963970" },
+        PackedArithTC{ NewPackedDec64(1, 22), NewPackedDec64(36, 15),
+                "10000036000000000000000" },
+        PackedArithTC{ NewPackedDec64(1, 40), NewPackedDec64(1, 0),
+                "10000000000000000000000000000000000000000" },
+        PackedArithTC{ NewPackedDec64(1, 40), NewPackedDec64(-1, 0),
+                "10000000000000000000000000000000000000000" },
+        // 1E16-0.5 rounds to 1E16 (ties away)
+        PackedArithTC{ NewPackedDec64(1, 16), NewPackedDec64(-5, -1),
+                "10000000000000000" },
+        // 1E16-0.51 rounds to 1E16-1
+        PackedArithTC{ NewPackedDec64(1, 16), NewPackedDec64(-51, -2),
+                "9999999999999999" },
+        PackedArithTC{ NewPackedDec64(1, 20), NewPackedDec64(-51, -2),
+                "100000000000000000000" },
+        PackedArithTC{ NewPackedDec64(1, 0), PackedNaN, "NaN" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.Add(tc.b)
+        if tc.expected!=result.String() {
+            t.Errorf("Result mismatch: %d: add(%v,%v)->%v!=%v",
+                     i, tc.a, tc.b, tc.expected, result)
+        }
+        result = tc.b.Add(tc.a)
+        if tc.expected!=result.String() {
+            t.Errorf("Result mismatch: %d: add(%v,%v)->%v!=%v",
+                     i, tc.b, tc.a, tc.expected, result)
+        }
+    }
+}
+
+func TestPackedDec64Sub(t *testing.T) {
+    testCases := []PackedArithTC {
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(1, 0), "122.45" },
+        PackedArithTC{ NewPackedDec64(1, 0), NewPackedDec64(12345, -2), "-122.45" },
+        PackedArithTC{ NewPackedDec64(1, 0), NewPackedDec64(-12345, -2), "124.45" },
+        PackedArithTC{ NewPackedDec64(3, -1), NewPackedDec64(1, -1), "0.2" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.Sub(tc.b)
+        if tc.expected!=result.String() {
+            t.Errorf("Result mismatch: %d: sub(%v,%v)->%v!=%v",
+                     i, tc.a, tc.b, tc.expected, result)
+        }
+    }
+}
+
+func TestPackedDec64Mul(t *testing.T) {
+    testCases := []PackedArithTC {
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(2, 0), "246.90" },
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(-2, -1), "-24.690" },
+        PackedArithTC{ NewPackedDec64(-3, 0), NewPackedDec64(-3, 0), "9" },
+        PackedArithTC{ NewPackedDec64(36028797018963967, 0),
+                NewPackedDec64(36028797018963967, 0), "1298074214633706800000000000000000" },
+        PackedArithTC{ NewPackedDec64(1, 100), NewPackedDec64(1, 100), "NaN" },
+        PackedArithTC{ NewPackedDec64(1, -100), NewPackedDec64(1, -100), "0" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.Mul(tc.b)
+        if tc.expected!=result.String() {
+            t.Errorf("Result mismatch: %d: mul(%v,%v)->%v!=%v",
+                     i, tc.a, tc.b, tc.expected, result)
+        }
+    }
+}
+
+func TestPackedDec64Div(t *testing.T) {
+    testCases := []PackedArithTC {
+        PackedArithTC{ NewPackedDec64(1, 0), NewPackedDec64(3, 0),
+                "0.33333333333333333" },
+        PackedArithTC{ NewPackedDec64(2, 0), NewPackedDec64(3, 0),
+                "0.6666666666666667" },
+        PackedArithTC{ NewPackedDec64(-2, 0), NewPackedDec64(3, 0),
+                "-0.6666666666666667" },
+        PackedArithTC{ NewPackedDec64(12345, -2), NewPackedDec64(5, 0),
+                "24.690000000000000" },
+        PackedArithTC{ NewPackedDec64(1, 0), NewPackedDec64(0, 0), "NaN" },
+        PackedArithTC{ NewPackedDec64(0, 0), NewPackedDec64(7, 0), "0" },
+    }
+    for i, tc := range testCases {
+        result := tc.a.Div(tc.b)
+        if tc.expected!=result.String() {
+            t.Errorf("Result mismatch: %d: div(%v,%v)->%v!=%v",
+                     i, tc.a, tc.b, tc.expected, result)
+        }
+    }
+}
+
+type PackedCmpTC struct {
+    a, b PackedDec64
+    expected int
+}
+
+func TestPackedDec64Cmp(t *testing.T) {
+    testCases := []PackedCmpTC {
+        PackedCmpTC{ NewPackedDec64(12345, -2), NewPackedDec64(123450, -3), 0 },
+        PackedCmpTC{ NewPackedDec64(12345, -2), NewPackedDec64(123451, -3), -1 },
+        PackedCmpTC{ NewPackedDec64(12345, -2), NewPackedDec64(-123451, -3), 1 },
+        PackedCmpTC{ NewPackedDec64(-12345, -2), NewPackedDec64(-123451, -3), 1 },
+        PackedCmpTC{ NewPackedDec64(1, 30), NewPackedDec64(36028797018963967, 0), 1 },
+        PackedCmpTC{ NewPackedDec64(0, 0), NewPackedDec64(-1, -100), 1 },
+        PackedCmpTC{ NewPackedDec64(0, 0), NewPackedDec64(0, 5), 0 },
+        PackedCmpTC{ PackedNaN, NewPackedDec64(0, 5), -1 },
+    }
+    for i, tc := range testCases {
+        result := tc.a.Cmp(tc.b)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: cmp(%v,%v)->%v!=%v",
+                     i, tc.a, tc.b, tc.expected, result)
+        }
+        result = tc.b.Cmp(tc.a)
+        if -tc.expected!=result {
+            t.Errorf("Result mismatch: %d: cmp(%v,%v)->%v!=%v",
+                     i, tc.b, tc.a, -tc.expected, result)
+        }
+    }
+}
+
+func TestPackedNaN(t *testing.T) {
+    // DEC64 canonical NaN: coefficient 0, exponent -128
+    if uint64(PackedNaN)!=0x80 {
+        t.Errorf("Result mismatch: nan bits %x!=80", uint64(PackedNaN))
+    }
+    if PackedNaN.Coefficient()!=0 || PackedNaN.Exponent()!=-128 || !PackedNaN.IsNaN() {
+        t.Errorf("Result mismatch: nan %v,%v,%v", PackedNaN.Coefficient(),
+                 PackedNaN.Exponent(), PackedNaN.IsNaN())
+    }
+    results := []PackedDec64{
+        NewPackedDec64(1, 0).Div(0),
+        NewPackedDec64(1, 0).Add(PackedNaN),
+        NewPackedDec64(1, 127).Mul(NewPackedDec64(1<<50, 127)),
+    }
+    for i, p := range results {
+        if uint64(p)!=0x80 {
+            t.Errorf("Result mismatch: %d: nan bits %x!=80", i, uint64(p))
+        }
+    }
+}
+
+type PackedNegTC struct {
+    a PackedDec64
+    expected string
+}
+
+func TestPackedDec64Neg(t *testing.T) {
+    testCases := []PackedNegTC {
+        PackedNegTC{ NewPackedDec64(123, -2), "-1.23" },
+        PackedNegTC{ NewPackedDec64(-5, 3), "5000" },
+        PackedNegTC{ PackedNaN, "NaN" },
+    }
+    for i, tc := range testCases {
+        if result := tc.a.Neg().String(); tc.expected!=result {
+            t.Errorf("Result mismatch: %d: neg(%v)->%v!=%v", i, tc.a,
+                     tc.expected, result)
+        }
+    }
+    // minimal coefficient -2^55 is rescaled
+    p := PackedDec64(-1<<63 | 0xfe)
+    n := p.Neg()
+    if n.Coefficient()!=3602879701896397 || n.Exponent()!=-1 {
+        t.Errorf("Result mismatch: neg(%v,%v)->%v,%v", p.Coefficient(),
+                 p.Exponent(), n.Coefficient(), n.Exponent())
+    }
+}
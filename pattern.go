@@ -0,0 +1,318 @@
+/*
+ * pattern.go - ICU/CLDR decimal format patterns
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "errors"
+    "strconv"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+)
+
+// invalid or unsupported number pattern
+var ErrPattern = errors.New("godec64: invalid number pattern")
+
+// currency sign placeholder in compiled affixes
+const patternCurrency = '\x00'
+
+// compiled ICU/CLDR decimal format pattern
+type NumberPattern struct {
+    posPrefix, posSuffix string
+    negPrefix, negSuffix string
+    minInt uint
+    minFrac, maxFrac uint
+    primaryGroup, secondaryGroup int // 0 - no grouping
+    shift uint // 2 for percent, 3 for per mille
+    // currency symbol put in place of '¤'
+    Currency string
+    // rounding mode used if value has more digits than maximal fraction digits
+    // (default is half to even as in ICU)
+    Rounding RoundingMode
+}
+
+// parse affix (prefix or suffix) from pattern, returns affix and position
+// after affix
+func parsePatternAffix(pattern string, i int, prefix bool,
+                       shift *uint) (string, int, error) {
+    var sb strings.Builder
+    for i < len(pattern) {
+        r, size := utf8.DecodeRuneInString(pattern[i:])
+        switch r {
+        case '\'':
+            j := i+1
+            if j < len(pattern) && pattern[j]=='\'' {
+                sb.WriteByte('\'')
+                i = j+1
+                continue
+            }
+            // quoted text, '' inside is single quote
+            for ; j < len(pattern); j++ {
+                if pattern[j]=='\'' {
+                    if j+1 < len(pattern) && pattern[j+1]=='\'' {
+                        j++
+                    } else {
+                        break
+                    }
+                }
+                sb.WriteByte(pattern[j])
+            }
+            if j==len(pattern) { return "", i, ErrPattern }
+            i = j+1
+            continue
+        case ';':
+            return sb.String(), i, nil
+        case '#', '0', ',', '.', '@', '1', '2', '3', '4', '5', '6', '7', '8',
+                '9', '*':
+            if prefix { return sb.String(), i, nil }
+            return "", i, ErrPattern
+        case '%':
+            *shift = 2
+            sb.WriteRune(r)
+        case '‰':
+            *shift = 3
+            sb.WriteRune(r)
+        case '¤':
+            sb.WriteByte(patternCurrency)
+            // ¤¤ and ¤¤¤ are treated as single currency sign
+            for ; i+size < len(pattern) && strings.HasPrefix(pattern[i+size:], "¤");
+                    i += size { }
+        default:
+            sb.WriteRune(r)
+        }
+        i += size
+    }
+    return sb.String(), i, nil
+}
+
+// parse number part of pattern
+func (p *NumberPattern) parseNumber(pattern string, i int) (int, error) {
+    var groups [3]int // sizes of last integer digit groups
+    ngroups := 1
+    afterPoint := false
+    seenZero, seenHash := false, false
+    digits := 0
+loop:
+    for ; i < len(pattern); i++ {
+        switch pattern[i] {
+        case '#':
+            if afterPoint {
+                p.maxFrac++
+                seenHash = true
+            } else {
+                if seenZero { return i, ErrPattern }
+                groups[0]++
+            }
+            digits++
+        case '0':
+            if afterPoint {
+                if seenHash { return i, ErrPattern }
+                p.minFrac++
+                p.maxFrac++
+            } else {
+                p.minInt++
+                seenZero = true
+                groups[0]++
+            }
+            digits++
+        case ',':
+            if afterPoint || groups[0]==0 { return i, ErrPattern }
+            groups[2], groups[1], groups[0] = groups[1], groups[0], 0
+            ngroups++
+        case '.':
+            if afterPoint { return i, ErrPattern }
+            afterPoint = true
+        case '@', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'E', '*':
+            return i, ErrPattern // unsupported
+        default:
+            break loop
+        }
+    }
+    if digits==0 { return i, ErrPattern }
+    if ngroups > 1 {
+        if groups[0]==0 { return i, ErrPattern }
+        p.primaryGroup = groups[0]
+        p.secondaryGroup = groups[0]
+        if ngroups > 2 { p.secondaryGroup = groups[1] }
+    }
+    return i, nil
+}
+
+// compile ICU/CLDR decimal format pattern, for example "#,##0.00",
+// "#,##,##0.###", "0.00%" or "#,##0.00;(#,##0.00)". Supported are grouping
+// (primary and secondary size), minimal integer digits, minimal and maximal
+// fraction digits, prefixes and suffixes (with quoting), percent, per mille,
+// currency sign and negative subpattern. Significant digits, exponent,
+// padding and rounding increments are not supported. Digits, comma and
+// grouping separator are rendered through locale, but percent, per mille
+// and default minus signs are always written as '%', '‰' and '-' (locales
+// as ar or fa use own signs)
+func CompilePattern(pattern string) (*NumberPattern, error) {
+    p := &NumberPattern{ Currency: "¤", Rounding: RoundHalfEven }
+    var err error
+    i := 0
+    p.posPrefix, i, err = parsePatternAffix(pattern, i, true, &p.shift)
+    if err!=nil { return nil, err }
+    if i, err = p.parseNumber(pattern, i); err!=nil {
+        return nil, err
+    }
+    p.posSuffix, i, err = parsePatternAffix(pattern, i, false, &p.shift)
+    if err!=nil { return nil, err }
+    if i==len(pattern) {
+        p.negPrefix = "-" + p.posPrefix
+        p.negSuffix = p.posSuffix
+        return p, nil
+    }
+    // negative subpattern: only prefix and suffix are used
+    var shift uint
+    p.negPrefix, i, err = parsePatternAffix(pattern, i+1, true, &shift)
+    if err!=nil { return nil, err }
+    neg := NumberPattern{}
+    if i, err = neg.parseNumber(pattern, i); err!=nil {
+        return nil, err
+    }
+    p.negSuffix, i, err = parsePatternAffix(pattern, i, false, &shift)
+    if err!=nil || i!=len(pattern) {
+        return nil, ErrPattern
+    }
+    return p, nil
+}
+
+// returns true if currency symbol needs space next to number (CLDR currency
+// spacing): character of symbol next to number is not a symbol character
+func currencyNeedsSpace(symbol string, prefix bool) bool {
+    var r rune
+    if prefix {
+        r, _ = utf8.DecodeLastRuneInString(symbol)
+    } else {
+        r, _ = utf8.DecodeRuneInString(symbol)
+    }
+    return r!=utf8.RuneError && !unicode.IsSymbol(r) && !unicode.IsSpace(r)
+}
+
+// append affix with currency symbol. No-break space is inserted between
+// currency symbol and number if needed (for example "CHF 12.50")
+func (p *NumberPattern) appendAffix(dst []byte, affix string, prefix bool) []byte {
+    for i:=0; i < len(affix); i++ {
+        if affix[i]==patternCurrency {
+            if !prefix && i==0 && currencyNeedsSpace(p.Currency, false) {
+                dst = append(dst, "\u00a0"...)
+            }
+            dst = append(dst, p.Currency...)
+            if prefix && i==len(affix)-1 && currencyNeedsSpace(p.Currency, true) {
+                dst = append(dst, "\u00a0"...)
+            }
+        } else {
+            dst = append(dst, affix[i])
+        }
+    }
+    return dst
+}
+
+// append number formatted by pattern to dst using symbols and digits of locale
+func (p *NumberPattern) AppendFormat(dst []byte, a UDec64, precision uint,
+                                     negative bool, l *LocFmt) []byte {
+    v := uint64(a)
+    // scale is number of digits after comma (can be negative)
+    scale := int(precision)-int(p.shift)
+    if scale > int(p.maxFrac) {
+        v = divPow10(v, uint(scale-int(p.maxFrac)), p.Rounding)
+        scale = int(p.maxFrac)
+    }
+    var buf [24]byte
+    s := strconv.AppendUint(buf[:0], v, 10)
+    if v==0 { s = s[:0] }
+    // integer and fraction digits
+    var intPart, fracPart []byte
+    var fracBuf [32]byte
+    switch {
+    case scale <= 0:
+        intPart = s
+    case len(s) > scale:
+        intPart, fracPart = s[:len(s)-scale], s[len(s)-scale:]
+    default:
+        fracPart = fracBuf[:0]
+        for k:=len(s); k < scale; k++ {
+            fracPart = append(fracPart, '0')
+        }
+        fracPart = append(fracPart, s...)
+    }
+    intZeroes := 0 // zeroes after integer digits
+    if scale < 0 && len(intPart)!=0 { intZeroes = -scale }
+    // trim trailing zeroes of fraction
+    for ; len(fracPart) > int(p.minFrac) && fracPart[len(fracPart)-1]=='0'; {
+        fracPart = fracPart[:len(fracPart)-1]
+    }
+    intLen := len(intPart)+intZeroes
+    leadZeroes := 0
+    if intLen < int(p.minInt) {
+        leadZeroes = int(p.minInt)-intLen
+    }
+    if intLen+leadZeroes==0 && len(fracPart)==0 && p.minFrac==0 {
+        leadZeroes = 1 // at least one digit
+    }
+    if negative && v!=0 {
+        dst = p.appendAffix(dst, p.negPrefix, true)
+    } else {
+        dst = p.appendAffix(dst, p.posPrefix, true)
+    }
+    total := leadZeroes+intLen
+    for k:=0; k < total; k++ {
+        d := byte(0)
+        if k >= leadZeroes && k-leadZeroes < len(intPart) {
+            d = intPart[k-leadZeroes]-'0'
+        }
+        dst = utf8.AppendRune(dst, l.Digits[d])
+        r := total-k-1 // remaining digits
+        if p.primaryGroup!=0 && r > 0 && (r==p.primaryGroup ||
+            (r > p.primaryGroup && (r-p.primaryGroup)%p.secondaryGroup==0)) {
+            dst = utf8.AppendRune(dst, l.Sep1000)
+        }
+    }
+    for k:=len(fracPart); k < int(p.minFrac); k++ {
+        fracPart = append(fracPart, '0')
+    }
+    if len(fracPart)!=0 {
+        dst = utf8.AppendRune(dst, l.Comma)
+        for _, c := range fracPart {
+            dst = utf8.AppendRune(dst, l.Digits[c-'0'])
+        }
+    }
+    if negative && v!=0 {
+        dst = p.appendAffix(dst, p.negSuffix, false)
+    } else {
+        dst = p.appendAffix(dst, p.posSuffix, false)
+    }
+    return dst
+}
+
+// format number by pattern using symbols and digits of locale
+func (p *NumberPattern) Format(a UDec64, precision uint, lang string) string {
+    return string(p.AppendFormat(nil, a, precision, false, GetLocFmt(lang)))
+}
+
+// format number with sign by pattern using symbols and digits of locale
+func (p *NumberPattern) FormatSigned(a UDec64, precision uint, negative bool,
+                                     lang string) string {
+    return string(p.AppendFormat(nil, a, precision, negative, GetLocFmt(lang)))
+}
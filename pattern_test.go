@@ -0,0 +1,126 @@
+/*
+ * pattern_test.go - ICU/CLDR decimal format pattern tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "testing"
+)
+
+type PatternFormatTC struct {
+    pattern string
+    lang string
+    value UDec64
+    precision uint
+    negative bool
+    expected string
+}
+
+func TestPatternFormat(t *testing.T) {
+    testCases := []PatternFormatTC {
+        PatternFormatTC{ "#,##0.00", "en", 123456789, 2, false, "1,234,567.89" },
+        PatternFormatTC{ "#,##0.00", "en", 123456789, 3, false, "123,456.79" },
+        PatternFormatTC{ "#,##0.00", "en", 5, 0, false, "5.00" },
+        PatternFormatTC{ "#,##0.00", "en", 0, 2, false, "0.00" },
+        PatternFormatTC{ "#,##0.00", "en", 0xffffffffffffffff, 0, false,
+                "18,446,744,073,709,551,615.00" },
+        PatternFormatTC{ "#,##0.00", "en", 12345, 2, true, "-123.45" },
+        PatternFormatTC{ "#,##0.00", "en", 4, 3, true, "0.00" },
+        PatternFormatTC{ "#,##0.00", "de", 123456789, 2, false, "1.234.567,89" },
+        PatternFormatTC{ "#,##,##0.###", "en", 123456789, 2, false, "12,34,567.89" },
+        PatternFormatTC{ "#,##,##0.###", "en", 1234567891, 4, false, "1,23,456.789" },
+        PatternFormatTC{ "#,##,##0.###", "en", 1200, 3, false, "1.2" },
+        PatternFormatTC{ "#,##,##0.###", "en", 1000, 3, false, "1" },
+        PatternFormatTC{ "#,##,##0.###", "en", 123456789, 0, false,
+                "12,34,56,789" },
+        PatternFormatTC{ "#,##,##0.###", "ar", 123456789, 2, false, "١٢٬٣٤٬٥٦٧٫٨٩" },
+        PatternFormatTC{ "0.00%", "en", 1234, 4, false, "12.34%" },
+        PatternFormatTC{ "0.00%", "en", 1, 0, false, "100.00%" },
+        PatternFormatTC{ "0.00%", "en", 12345, 5, false, "12.34%" },
+        PatternFormatTC{ "#,##0%", "en", 123, 1, false, "1,230%" },
+        PatternFormatTC{ "0.0‰", "en", 1234, 4, false, "123.4‰" },
+        PatternFormatTC{ "#,##0.00;(#,##0.00)", "en", 123456, 2, true, "(1,234.56)" },
+        PatternFormatTC{ "#,##0.00;(#,##0.00)", "en", 123456, 2, false, "1,234.56" },
+        PatternFormatTC{ "#,##0.00;(#)", "en", 123456, 2, true, "(1,234.56)" },
+        PatternFormatTC{ "¤#,##0.00", "en", 123456, 2, false, "¤1,234.56" },
+        PatternFormatTC{ "#,##0.00 ¤¤", "en", 123456, 2, true, "-1,234.56 ¤" },
+        PatternFormatTC{ "'#'0", "en", 5, 0, false, "#5" },
+        PatternFormatTC{ "0 'o''clock'", "en", 5, 0, false, "5 o'clock" },
+        PatternFormatTC{ "0'%'", "en", 5, 0, false, "5%" },
+        PatternFormatTC{ "00000", "en", 42, 0, false, "00042" },
+        PatternFormatTC{ "#,#00", "en", 1, 0, false, "01" },
+        PatternFormatTC{ "#,#00", "en", 12345, 0, false, "12,345" },
+        PatternFormatTC{ "#", "en", 0, 0, false, "0" },
+        PatternFormatTC{ "#.##", "en", 5, 1, false, ".5" },
+        PatternFormatTC{ "#.##", "en", 0, 1, false, "0" },
+        PatternFormatTC{ ".00", "en", 5, 1, false, ".50" },
+        PatternFormatTC{ "0.###############################", "en", 5, 19, false,
+                "0.0000000000000000005" },
+        PatternFormatTC{ "#,##0.00", "fr", 123456789, 2, false, "1\u202f234\u202f567,89" },
+    }
+    for i, tc := range testCases {
+        p, err := CompilePattern(tc.pattern)
+        if err!=nil {
+            t.Errorf("Compile error: %d: %v: %v", i, tc.pattern, err)
+            continue
+        }
+        result := p.FormatSigned(tc.value, tc.precision, tc.negative, tc.lang)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: format(%v,%v,%v,%v,%v)->%q!=%q",
+                     i, tc.pattern, tc.lang, tc.value, tc.precision, tc.negative,
+                     tc.expected, result)
+        }
+    }
+    p, _ := CompilePattern("¤#,##0.00")
+    p.Currency = "$"
+    p.Rounding = RoundHalfUp
+    if result := p.Format(12345, 3, "en"); result!="$12.35" {
+        t.Errorf("Result mismatch: currency->%v", result)
+    }
+    // currency spacing: alphabetic symbol is separated from number
+    p.Currency = "CHF"
+    if result := p.FormatSigned(12345, 3, true, "en"); result!="-CHF\u00a012.35" {
+        t.Errorf("Result mismatch: currency->%q", result)
+    }
+    p, _ = CompilePattern("#,##0.00¤;#,##0.00-¤")
+    p.Currency = "kr."
+    if result := p.Format(12345, 2, "en"); result!="123.45\u00a0kr." {
+        t.Errorf("Result mismatch: currency->%q", result)
+    }
+    if result := p.FormatSigned(12345, 2, true, "en"); result!="123.45-kr." {
+        t.Errorf("Result mismatch: currency->%q", result)
+    }
+    p.Currency = "€"
+    if result := p.Format(12345, 2, "en"); result!="123.45€" {
+        t.Errorf("Result mismatch: currency->%q", result)
+    }
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+    invalidPatterns := []string{ "", "abc", "#,##0.00#0", "0#", "#,##0.0,0",
+        "0.0.0", "#,##0,", "@@@", "#,##0.05", "0.0E0", "*x#0", "0 %x'",
+        "0;", "0;0;0", "0;x", "0x0", "0.00;(0.00)0" }
+    for i, pattern := range invalidPatterns {
+        if _, err := CompilePattern(pattern); err!=ErrPattern {
+            t.Errorf("Error mismatch: %d: %v->%v", i, pattern, err)
+        }
+    }
+}
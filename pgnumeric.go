@@ -0,0 +1,101 @@
+/*
+ * pgnumeric.go - PostgreSQL NUMERIC binary format
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "encoding/binary"
+    "strconv"
+)
+
+// PostgreSQL NUMERIC sign field values
+const (
+    pgNumericPos = 0x0000
+    pgNumericNeg = 0x4000
+    pgNumericNaN = 0xc000
+    pgNumericPInf = 0xd000
+    pgNumericNInf = 0xf000
+)
+
+// append PostgreSQL NUMERIC binary form (ndigits, weight, sign, dscale and
+// base-10000 digits, all big endian) to dst. Display scale is precision
+func (a UDec64) AppendPGNumeric(dst []byte, precision uint) []byte {
+    var dbuf [48]byte
+    digits, weight := groupedDigits(dbuf[:0], uint64(a), precision, 4)
+    ndigits := len(digits)/4
+    dst = binary.BigEndian.AppendUint16(dst, uint16(ndigits))
+    dst = binary.BigEndian.AppendUint16(dst, uint16(int16(weight)))
+    dst = binary.BigEndian.AppendUint16(dst, pgNumericPos)
+    dst = binary.BigEndian.AppendUint16(dst, uint16(precision))
+    for i:=0; i < ndigits; i++ {
+        g := digits[i*4:i*4+4]
+        d := uint16(g[0])*1000 + uint16(g[1])*100 + uint16(g[2])*10 + uint16(g[3])
+        dst = binary.BigEndian.AppendUint16(dst, d)
+    }
+    return dst
+}
+
+// convert to PostgreSQL NUMERIC binary form
+func (a UDec64) ToPGNumeric(precision uint) []byte {
+    return a.AppendPGNumeric(nil, precision)
+}
+
+// convert PostgreSQL NUMERIC binary form to UDec64 with precision and
+// rounding mode. Display scale is ignored
+func PGNumericToUDec64(b []byte, precision uint,
+                       mode RoundingMode) (UDec64, error) {
+    if len(b) < 8 {
+        return 0, strconv.ErrSyntax
+    }
+    ndigits := int(int16(binary.BigEndian.Uint16(b)))
+    weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+    sign := binary.BigEndian.Uint16(b[4:])
+    switch sign {
+    case pgNumericPos, pgNumericNeg:
+    case pgNumericNaN:
+        return 0, ErrNaN
+    case pgNumericPInf, pgNumericNInf:
+        return 0, ErrInfinity
+    default:
+        return 0, strconv.ErrSyntax
+    }
+    if ndigits < 0 || len(b)!=8+2*ndigits {
+        return 0, strconv.ErrSyntax
+    }
+    var dbuf [80]byte
+    digits := dbuf[:0]
+    zero := true
+    for i:=0; i < ndigits; i++ {
+        d := binary.BigEndian.Uint16(b[8+2*i:])
+        if d >= 10000 {
+            return 0, strconv.ErrSyntax
+        }
+        if d!=0 { zero = false }
+        digits = append(digits, byte(d/1000), byte(d/100%10), byte(d/10%10),
+                        byte(d%10))
+    }
+    if zero { return 0, nil }
+    if sign==pgNumericNeg {
+        return 0, strconv.ErrRange // negative
+    }
+    return digitsToUDec64(digits, (weight-ndigits+1)*4, precision, mode)
+}
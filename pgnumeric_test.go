@@ -0,0 +1,131 @@
+/*
+ * pgnumeric_test.go - PostgreSQL NUMERIC binary format tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "encoding/hex"
+    "strconv"
+    "testing"
+)
+
+type UDec64ToPGNumericTC struct {
+    value UDec64
+    precision uint
+    expected string
+}
+
+func TestUDec64ToPGNumeric(t *testing.T) {
+    testCases := []UDec64ToPGNumericTC {
+        // 0, 0.00
+        UDec64ToPGNumericTC{ 0, 0, "0000000000000000" },
+        UDec64ToPGNumericTC{ 0, 2, "0000000000000002" },
+        // 123.45
+        UDec64ToPGNumericTC{ 12345, 2, "0002000000000002007b1194" },
+        // 100000000
+        UDec64ToPGNumericTC{ 100000000, 0, "00010002000000000001" },
+        // 0.000005
+        UDec64ToPGNumericTC{ 5, 6, "0001fffe0000000601f4" },
+        // 1.00
+        UDec64ToPGNumericTC{ 100, 2, "00010000000000020001" },
+        // 10000.0001
+        UDec64ToPGNumericTC{ 100000001, 4, "0003000100000004000100000001" },
+        UDec64ToPGNumericTC{ 0xffffffffffffffff, 0,
+                "000500040000000007341a5802e103bb064f" },
+        // 18.446744073709551615
+        UDec64ToPGNumericTC{ 0xffffffffffffffff, 18,
+                "00060000000000120012117311370e7d158c05dc" },
+    }
+    for i, tc := range testCases {
+        result := tc.value.ToPGNumeric(tc.precision)
+        if tc.expected!=hex.EncodeToString(result) {
+            t.Errorf("Result mismatch: %d: topgnumeric(%v,%v)->%v!=%x",
+                     i, tc.value, tc.precision, tc.expected, result)
+        }
+        back, err := PGNumericToUDec64(result, tc.precision, RoundDown)
+        if back!=tc.value || err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%v->%x->%v,%v",
+                     i, tc.value, tc.precision, result, back, err)
+        }
+    }
+}
+
+type PGNumericToUDec64TC struct {
+    value string
+    precision uint
+    mode RoundingMode
+    expected UDec64
+    expError error
+}
+
+func TestPGNumericToUDec64(t *testing.T) {
+    testCases := []PGNumericToUDec64TC {
+        PGNumericToUDec64TC{ "0000000000000000", 2, RoundDown, 0, nil },
+        // "-0" and zero digits
+        PGNumericToUDec64TC{ "0000000040000000", 2, RoundDown, 0, nil },
+        PGNumericToUDec64TC{ "00010000000000000000", 2, RoundDown, 0, nil },
+        // 123.45
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 2, RoundDown, 12345, nil },
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 4, RoundDown, 1234500, nil },
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 1, RoundDown, 1234, nil },
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 1, RoundHalfUp, 1235, nil },
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 1, RoundHalfEven, 1234, nil },
+        PGNumericToUDec64TC{ "0002000000000002007b1194", 0, RoundUp, 124, nil },
+        // 0.000123456789
+        PGNumericToUDec64TC{ "0003ffff0000000c000109291a85", 12, RoundDown,
+                123456789, nil },
+        PGNumericToUDec64TC{ "0003ffff0000000c000109291a85", 6, RoundHalfUp, 123, nil },
+        PGNumericToUDec64TC{ "0003ffff0000000c000109291a85", 7, RoundHalfUp, 1235, nil },
+        PGNumericToUDec64TC{ "0003ffff0000000c000109291a85", 2, RoundDown, 0, nil },
+        PGNumericToUDec64TC{ "0003ffff0000000c000109291a85", 2, RoundUp, 1, nil },
+        // 100000000
+        PGNumericToUDec64TC{ "00010002000000000001", 3, RoundDown,
+                100000000000, nil },
+        // 99999999999999999999, 1E+20
+        PGNumericToUDec64TC{ "0005000400000000270f270f270f270f270f", 0, RoundDown,
+                0, strconv.ErrRange },
+        PGNumericToUDec64TC{ "00010005000000000001", 0, RoundDown,
+                0, strconv.ErrRange },
+        // -1
+        PGNumericToUDec64TC{ "00010000400000000001", 0, RoundDown,
+                0, strconv.ErrRange },
+        // NaN, Infinity, -Infinity
+        PGNumericToUDec64TC{ "00000000c0000000", 2, RoundDown, 0, ErrNaN },
+        PGNumericToUDec64TC{ "00000000d0000000", 2, RoundDown, 0, ErrInfinity },
+        PGNumericToUDec64TC{ "00000000f0000000", 2, RoundDown, 0, ErrInfinity },
+        // bad sign, too short, bad length, bad digit
+        PGNumericToUDec64TC{ "0000000012340000", 2, RoundDown, 0, strconv.ErrSyntax },
+        PGNumericToUDec64TC{ "000000000000", 2, RoundDown, 0, strconv.ErrSyntax },
+        PGNumericToUDec64TC{ "00020000000000000001", 2, RoundDown,
+                0, strconv.ErrSyntax },
+        PGNumericToUDec64TC{ "00010000000000002710", 2, RoundDown,
+                0, strconv.ErrSyntax },
+    }
+    for i, tc := range testCases {
+        b, _ := hex.DecodeString(tc.value)
+        result, err := PGNumericToUDec64(b, tc.precision, tc.mode)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: pgnumerictoudec64(%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.value, tc.precision, tc.mode, tc.expected, tc.expError,
+                     result, err)
+        }
+    }
+}
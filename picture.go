@@ -0,0 +1,428 @@
+/*
+ * picture.go - COBOL PICTURE clause formatting and parsing
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "strconv"
+)
+
+// picture symbol kinds
+const (
+    picDigit uint8 = iota // 9
+    picZero // Z
+    picStar // *
+    picFloatSym // first symbol of floating string ($, + or -)
+    picFloat // digit position of floating string
+    picInsert // ',', B, 0 or /
+    picPoint // .
+    picImplied // V
+    picSign // fixed + or -
+    picCR // CR or DB
+    picCurrency // fixed $
+    picOpSign // S
+)
+
+type picSym struct {
+    kind uint8
+    ch byte
+}
+
+// compiled COBOL PICTURE clause
+type Picture struct {
+    syms []picSym
+    intDigits, fracDigits uint
+    width int
+    floatCh byte // '$', '+', '-' or 0 if no floating string
+    hasNine bool
+    hasZero bool
+    hasStar bool
+    signed bool
+    // rounding mode used if precision of value is greater than number of
+    // fraction digits of picture (default is truncation as in COBOL)
+    Rounding RoundingMode
+}
+
+// compile COBOL PICTURE clause for numeric and numeric-edited items.
+// Supported symbols: 9, Z, *, $, +, -, CR, DB, ',', '.', V, B, 0, /, S and
+// repetition in form X(n). Floating insertion is recognized if $, + or -
+// occurs more than once
+func CompilePicture(pic string) (*Picture, error) {
+    // expand repetitions
+    toks := make([]byte, 0, len(pic))
+    for i:=0; i < len(pic); i++ {
+        c := pic[i]
+        if c>='a' && c<='z' { c -= 'a'-'A' }
+        if i+1 < len(pic) && (c=='C' || c=='D') {
+            c2 := pic[i+1] &^ 0x20
+            if (c=='C' && c2=='R') || (c=='D' && c2=='B') {
+                toks = append(toks, c|0x20) // 'c' or 'd'
+                i++
+                continue
+            }
+        }
+        switch c {
+        case '9', 'Z', '*', '$', '+', '-', ',', '.', 'V', 'B', '0', '/', 'S':
+        default:
+            return nil, ErrCobolPic
+        }
+        n := 1
+        if i+1 < len(pic) && pic[i+1]=='(' {
+            j := i+2
+            for ; j < len(pic) && pic[j]!=')'; j++ { }
+            if j==len(pic) { return nil, ErrCobolPic }
+            var err error
+            n, err = strconv.Atoi(pic[i+2:j])
+            if err!=nil || n < 1 || n > cobolMaxDigits ||
+                c=='.' || c=='V' || c=='S' {
+                return nil, ErrCobolPic
+            }
+            i = j
+        }
+        for ; n > 0; n-- {
+            toks = append(toks, c)
+        }
+    }
+    p := &Picture{ syms: make([]picSym, 0, len(toks)) }
+    var counts [256]int
+    for _, c := range toks {
+        counts[c]++
+    }
+    for _, c := range []byte{ '$', '+', '-' } {
+        if counts[c] >= 2 {
+            if p.floatCh!=0 { return nil, ErrCobolPic }
+            p.floatCh = c
+        }
+    }
+    if counts['.']+counts['V'] > 1 {
+        return nil, ErrCobolPic
+    }
+    signs := counts['c']+counts['d']+counts['S']
+    for _, c := range []byte{ '+', '-' } {
+        if c==p.floatCh {
+            signs++ // floating sign is single sign
+        } else {
+            signs += counts[c]
+        }
+    }
+    if signs > 1 {
+        return nil, ErrCobolPic
+    }
+    p.signed = signs!=0
+    p.hasZero = counts['Z']!=0
+    p.hasStar = counts['*']!=0
+    afterPoint := false
+    floatState := 0 // 0 - before, 1 - in floating string, 2 - after
+    fracSuppress := false
+    suppressKinds := 0
+    if p.floatCh!=0 { suppressKinds++ }
+    if counts['Z']!=0 { suppressKinds++ }
+    if counts['*']!=0 { suppressKinds++ }
+    if suppressKinds > 1 {
+        return nil, ErrCobolPic
+    }
+    last := len(toks)-1
+    for i, c := range toks {
+        sym := picSym{ ch: c }
+        if p.floatCh!=0 && c==p.floatCh {
+            if floatState==2 || afterPoint || p.hasNine {
+                return nil, ErrCobolPic
+            }
+            if floatState==0 {
+                sym.kind = picFloatSym
+                floatState = 1
+            } else {
+                sym.kind = picFloat
+            }
+        } else {
+            if floatState==1 && c!=',' && c!='B' && c!='0' && c!='/' {
+                floatState = 2
+            }
+            switch c {
+            case '9':
+                sym.kind = picDigit
+                p.hasNine = true
+            case 'Z', '*':
+                if (p.hasNine && !afterPoint) { return nil, ErrCobolPic }
+                sym.kind = picZero
+                if c=='*' { sym.kind = picStar }
+                if afterPoint { fracSuppress = true }
+            case ',', 'B', '0', '/':
+                sym.kind = picInsert
+                if c=='B' { sym.ch = ' ' }
+            case '.':
+                sym.kind = picPoint
+                afterPoint = true
+            case 'V':
+                sym.kind = picImplied
+                afterPoint = true
+            case '+', '-':
+                if i!=0 && i!=last { return nil, ErrCobolPic }
+                sym.kind = picSign
+            case '$':
+                sym.kind = picCurrency
+            case 'c', 'd':
+                if i!=last { return nil, ErrCobolPic }
+                sym.kind = picCR
+            case 'S':
+                if i!=0 { return nil, ErrCobolPic }
+                sym.kind = picOpSign
+            }
+        }
+        switch sym.kind {
+        case picDigit, picZero, picStar, picFloat:
+            if afterPoint {
+                p.fracDigits++
+            } else {
+                p.intDigits++
+            }
+        }
+        switch sym.kind {
+        case picImplied, picOpSign:
+        case picCR:
+            p.width += 2
+        default:
+            p.width++
+        }
+        p.syms = append(p.syms, sym)
+    }
+    if fracSuppress && p.hasNine {
+        return nil, ErrCobolPic
+    }
+    if n := p.intDigits+p.fracDigits; n==0 || n > cobolMaxDigits {
+        return nil, ErrCobolPic
+    }
+    return p, nil
+}
+
+// get width of formatted value
+func (p *Picture) Width() int {
+    return p.width
+}
+
+// get numeric picture of value held by picture
+func (p *Picture) Pic() CobolPic {
+    return CobolPic{ p.intDigits, p.fracDigits, p.signed }
+}
+
+// append number formatted by picture to dst. If picture has no sign symbol,
+// sign of negative value is lost
+func (p *Picture) AppendFormat(dst []byte, a UDec64, precision uint,
+                               negative bool) ([]byte, error) {
+    v := uint64(a)
+    if p.fracDigits < precision {
+        v = divPow10(v, precision-p.fracDigits, p.Rounding)
+        precision = p.fracDigits
+    }
+    var digits [cobolMaxDigits]byte
+    if !fixedDigits(digits[:p.intDigits+p.fracDigits], v, precision,
+                    int(p.intDigits)) {
+        return dst, strconv.ErrRange
+    }
+    neg := negative && v!=0
+    repl := byte(' ')
+    if p.hasStar { repl = '*' }
+    if v==0 && !p.hasNine {
+        // whole item is spaces or asterisks (except decimal point)
+        for _, sym := range p.syms {
+            switch {
+            case sym.kind==picPoint && p.hasStar:
+                dst = append(dst, '.')
+            case sym.kind==picImplied || sym.kind==picOpSign:
+            case sym.kind==picCR:
+                dst = append(dst, repl, repl)
+            default:
+                dst = append(dst, repl)
+            }
+        }
+        return dst, nil
+    }
+    floatCh := p.floatCh
+    if floatCh=='+' && neg { floatCh = '-' }
+    if floatCh=='-' && !neg { floatCh = ' ' }
+    // suppress leading zeroes until first non-zero digit, 9 or decimal point
+    suppress := p.floatCh!=0 || p.hasZero || p.hasStar
+    floatPos := -1
+    k := 0
+    for _, sym := range p.syms {
+        if suppress {
+            switch sym.kind {
+            case picZero, picStar, picFloat:
+                if digits[k]==0 {
+                    if sym.kind==picFloat { floatPos = len(dst) }
+                    dst = append(dst, repl)
+                    k++
+                    continue
+                }
+                fallthrough
+            case picDigit, picPoint, picImplied:
+                suppress = false
+                if floatPos >= 0 { dst[floatPos] = floatCh }
+            case picFloatSym:
+                floatPos = len(dst)
+                dst = append(dst, ' ')
+                continue
+            case picInsert:
+                if p.floatCh!=0 && floatPos >= 0 { floatPos = len(dst) }
+                dst = append(dst, repl)
+                continue
+            }
+        }
+        switch sym.kind {
+        case picDigit, picZero, picStar, picFloat:
+            dst = append(dst, '0'+digits[k])
+            k++
+        case picInsert, picPoint:
+            dst = append(dst, sym.ch)
+        case picSign:
+            switch {
+            case neg:
+                dst = append(dst, '-')
+            case sym.ch=='+':
+                dst = append(dst, '+')
+            default:
+                dst = append(dst, ' ')
+            }
+        case picCR:
+            switch {
+            case !neg:
+                dst = append(dst, ' ', ' ')
+            case sym.ch=='c':
+                dst = append(dst, 'C', 'R')
+            default:
+                dst = append(dst, 'D', 'B')
+            }
+        case picCurrency:
+            dst = append(dst, '$')
+        }
+    }
+    return dst, nil
+}
+
+// format number by picture
+func (p *Picture) Format(a UDec64, precision uint) (string, error) {
+    return p.FormatSigned(a, precision, false)
+}
+
+// format number with sign by picture
+func (p *Picture) FormatSigned(a UDec64, precision uint,
+                               negative bool) (string, error) {
+    var buf [64]byte
+    out, err := p.AppendFormat(buf[:0], a, precision, negative)
+    if err!=nil { return "", err }
+    return string(out), nil
+}
+
+// parse number formatted by picture (de-editing). String shorter than width
+// of picture is treated as padded by spaces at left. Returns strconv.ErrRange
+// if number is negative
+func (p *Picture) Parse(s string, precision uint) (UDec64, error) {
+    v, neg, err := p.ParseSigned(s, precision)
+    if err==nil && neg {
+        return 0, strconv.ErrRange
+    }
+    return v, err
+}
+
+// parse number formatted by picture (de-editing) with sign
+func (p *Picture) ParseSigned(s string, precision uint) (UDec64, bool, error) {
+    if len(s) > p.width {
+        return 0, false, strconv.ErrSyntax
+    }
+    pad := p.width-len(s)
+    pos := 0
+    at := func() byte {
+        c := byte(' ')
+        if pos >= pad { c = s[pos-pad] }
+        pos++
+        return c
+    }
+    isFloat := func(c byte) bool {
+        return p.floatCh!=0 && (c==p.floatCh || (p.floatCh=='+' && c=='-'))
+    }
+    var digits [cobolMaxDigits]byte
+    k := 0
+    neg := false
+    for _, sym := range p.syms {
+        switch sym.kind {
+        case picDigit, picZero, picStar, picFloat:
+            c := at()
+            switch {
+            case c>='0' && c<='9':
+                digits[k] = c-'0'
+            case sym.kind!=picDigit && (c==' ' || c=='*'):
+                digits[k] = 0
+            case sym.kind==picFloat && isFloat(c):
+                digits[k] = 0
+                neg = neg || c=='-'
+            default:
+                return 0, false, strconv.ErrSyntax
+            }
+            k++
+        case picFloatSym:
+            c := at()
+            if isFloat(c) {
+                neg = neg || c=='-'
+            } else if c!=' ' {
+                return 0, false, strconv.ErrSyntax
+            }
+        case picInsert:
+            c := at()
+            if c!=sym.ch && c!=' ' && c!='*' && !isFloat(c) {
+                return 0, false, strconv.ErrSyntax
+            }
+            neg = neg || (isFloat(c) && c=='-')
+        case picPoint:
+            if c := at(); c!='.' && c!=' ' && c!='*' {
+                return 0, false, strconv.ErrSyntax
+            }
+        case picSign:
+            switch at() {
+            case '-':
+                neg = true
+            case '+', ' ':
+            default:
+                return 0, false, strconv.ErrSyntax
+            }
+        case picCR:
+            c1, c2 := at(), at()
+            switch {
+            case (c1==' ' && c2==' ') || (c1=='*' && c2=='*'):
+            case (sym.ch=='c' && c1=='C' && c2=='R') ||
+                    (sym.ch=='d' && c1=='D' && c2=='B'):
+                neg = true
+            default:
+                return 0, false, strconv.ErrSyntax
+            }
+        case picCurrency:
+            if at()!='$' {
+                return 0, false, strconv.ErrSyntax
+            }
+        }
+    }
+    v, err := digitsToUDec64(digits[:k], -int(p.fracDigits), precision,
+                             p.Rounding)
+    if err!=nil {
+        return 0, false, err
+    }
+    return v, neg && v!=0, nil
+}
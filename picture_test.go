@@ -0,0 +1,202 @@
+/*
+ * picture_test.go - COBOL PICTURE clause formatting and parsing tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "strconv"
+    "testing"
+)
+
+func TestCompilePicture(t *testing.T) {
+    validPics := []struct{
+        pic string
+        width int
+        cpic CobolPic
+    }{
+        { "9(5)V99", 7, CobolPic{ 5, 2, false } },
+        { "S9(5)V99", 7, CobolPic{ 5, 2, true } },
+        { "ZZZ,ZZ9.99", 10, CobolPic{ 6, 2, false } },
+        { "$$$,$$9.99CR", 12, CobolPic{ 5, 2, true } },
+        { "$$$,$$9.99db", 12, CobolPic{ 5, 2, true } },
+        { "***,**9.99", 10, CobolPic{ 6, 2, false } },
+        { "**,***.**", 9, CobolPic{ 5, 2, false } },
+        { "+ZZ9", 4, CobolPic{ 3, 0, true } },
+        { "---9", 4, CobolPic{ 3, 0, true } },
+        { "+(3)9.99", 7, CobolPic{ 3, 2, true } },
+        { "$ZZ9-", 5, CobolPic{ 3, 0, true } },
+        { "99/99/99", 8, CobolPic{ 6, 0, false } },
+        { "999B999", 7, CobolPic{ 6, 0, false } },
+        { "9990", 4, CobolPic{ 3, 0, false } },
+        { "ZZZ.ZZ", 6, CobolPic{ 3, 2, false } },
+    }
+    for i, tc := range validPics {
+        p, err := CompilePicture(tc.pic)
+        if err!=nil {
+            t.Errorf("Compile error: %d: %v: %v", i, tc.pic, err)
+            continue
+        }
+        if tc.width!=p.Width() || tc.cpic!=p.Pic() {
+            t.Errorf("Result mismatch: %d: %v->%v,%v!=%v,%v", i, tc.pic,
+                     tc.width, tc.cpic, p.Width(), p.Pic())
+        }
+    }
+    invalidPics := []string{ "", "X(3)", "9(0)", "9(", "9(x)", "9.9.9", "9V9.9",
+        "ZZ9Z", "9$$$", "$$$++9", "ZZ**9", "$$Z9", "9+9", "CR99", "S9(3)-",
+        "9S", "+99-", "ZZ.Z9", "9.ZZ", "$$.$$", ".(2)", "V", "," }
+    for i, pic := range invalidPics {
+        if _, err := CompilePicture(pic); err!=ErrCobolPic {
+            t.Errorf("Error mismatch: %d: %v->%v", i, pic, err)
+        }
+    }
+}
+
+type PictureFormatTC struct {
+    pic string
+    value UDec64
+    precision uint
+    negative bool
+    expected string
+    expError error
+}
+
+func TestPictureFormat(t *testing.T) {
+    testCases := []PictureFormatTC {
+        PictureFormatTC{ "9(5)V99", 12345, 2, false, "0012345", nil },
+        PictureFormatTC{ "9(5)V99", 12345, 3, false, "0001234", nil },
+        PictureFormatTC{ "9(5)V99", 12345, 0, false, "1234500", nil },
+        PictureFormatTC{ "9(5)V99", 123456789, 2, false, "", strconv.ErrRange },
+        PictureFormatTC{ "S9(5)V99", 12345, 2, false, "0012345", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 1234567, 2, false, " 12,345.67", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 1234, 2, false, "     12.34", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 5, 2, false, "      0.05", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 0, 2, false, "      0.00", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 99999999, 2, false, "999,999.99", nil },
+        PictureFormatTC{ "ZZZ,ZZ9.99", 100000000, 2, false, "", strconv.ErrRange },
+        PictureFormatTC{ "ZZZ.ZZ", 0, 2, false, "      ", nil },
+        PictureFormatTC{ "ZZZ.ZZ", 50, 2, false, "   .50", nil },
+        PictureFormatTC{ "ZZZ.ZZ", 10050, 2, false, "100.50", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 1234567, 2, false, "$12,345.67  ", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 1234567, 2, true, "$12,345.67CR", nil },
+        PictureFormatTC{ "$$$,$$9.99DB", 1234567, 2, true, "$12,345.67DB", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 23400, 2, false, "   $234.00  ", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 123400, 2, true, " $1,234.00CR", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 5, 2, false, "     $0.05  ", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 0, 2, true, "     $0.00  ", nil },
+        PictureFormatTC{ "$$$,$$9.99CR", 12345678, 2, false, "", strconv.ErrRange },
+        PictureFormatTC{ "$$$.99", 50, 2, false, "  $.50", nil },
+        PictureFormatTC{ "***,**9.99", 1234, 2, false, "*****12.34", nil },
+        PictureFormatTC{ "***,**9.99", 123456, 2, false, "**1,234.56", nil },
+        PictureFormatTC{ "**,***.**", 0, 2, false, "******.**", nil },
+        PictureFormatTC{ "**,***.**", 1, 2, false, "******.01", nil },
+        PictureFormatTC{ "+ZZ9", 5, 0, false, "+  5", nil },
+        PictureFormatTC{ "+ZZ9", 5, 0, true, "-  5", nil },
+        PictureFormatTC{ "ZZ9-", 5, 0, false, "  5 ", nil },
+        PictureFormatTC{ "ZZ9-", 5, 0, true, "  5-", nil },
+        PictureFormatTC{ "---9", 5, 0, false, "   5", nil },
+        PictureFormatTC{ "---9", 5, 0, true, "  -5", nil },
+        PictureFormatTC{ "---9", 123, 0, true, "-123", nil },
+        PictureFormatTC{ "+++9", 123, 0, false, "+123", nil },
+        PictureFormatTC{ "+++9", 1234, 0, false, "", strconv.ErrRange },
+        PictureFormatTC{ "+(3)9.99", 1234, 2, true, " -12.34", nil },
+        PictureFormatTC{ "$ZZ9-", 42, 0, true, "$ 42-", nil },
+        PictureFormatTC{ "99/99/99", 123199, 0, false, "12/31/99", nil },
+        PictureFormatTC{ "999B999", 123456, 0, false, "123 456", nil },
+        PictureFormatTC{ "9990", 123, 0, false, "1230", nil },
+    }
+    for i, tc := range testCases {
+        p, err := CompilePicture(tc.pic)
+        if err!=nil {
+            t.Errorf("Compile error: %d: %v: %v", i, tc.pic, err)
+            continue
+        }
+        result, err := p.FormatSigned(tc.value, tc.precision, tc.negative)
+        if tc.expected!=result || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: format(%v,%v,%v,%v)->%v,%v!=%v,%v",
+                     i, tc.pic, tc.value, tc.precision, tc.negative,
+                     tc.expected, tc.expError, result, err)
+        }
+        if err!=nil || tc.precision > p.Pic().FracDigits { continue }
+        back, neg, err := p.ParseSigned(result, tc.precision)
+        if back!=tc.value || neg!=(tc.negative && tc.value!=0 && p.Pic().Signed) ||
+            err!=nil {
+            t.Errorf("Round trip mismatch: %d: %v,%q->%v,%v,%v",
+                     i, tc.pic, result, back, neg, err)
+        }
+    }
+    p, _ := CompilePicture("9(5)V99")
+    p.Rounding = RoundHalfUp
+    if result, _ := p.Format(12345, 3); result!="0001235" {
+        t.Errorf("Result mismatch: rounding->%v", result)
+    }
+}
+
+type PictureParseTC struct {
+    pic string
+    value string
+    precision uint
+    expected UDec64
+    expNeg bool
+    expError error
+}
+
+func TestPictureParse(t *testing.T) {
+    testCases := []PictureParseTC {
+        PictureParseTC{ "9(5)V99", "0012345", 2, 12345, false, nil },
+        PictureParseTC{ "9(5)V99", "0012345", 1, 1234, false, nil },
+        PictureParseTC{ "9(5)V99", "0012345", 4, 1234500, false, nil },
+        PictureParseTC{ "9(5)V99", "00123 5", 2, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "9(5)V99", "00123456", 2, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "ZZZ,ZZ9.99", " 12,345.67", 2, 1234567, false, nil },
+        PictureParseTC{ "ZZZ,ZZ9.99", "12.34", 2, 1234, false, nil },
+        PictureParseTC{ "ZZZ,ZZ9.99", "     12.3A", 2, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "ZZZ,ZZ9.99", "     12,34", 2, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "ZZZ.ZZ", "      ", 2, 0, false, nil },
+        PictureParseTC{ "$$$,$$9.99CR", "$12,345.67CR", 2, 1234567, true, nil },
+        PictureParseTC{ "$$$,$$9.99CR", "   $234.00  ", 2, 23400, false, nil },
+        PictureParseTC{ "$$$,$$9.99CR", "   $234.00DB", 2, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "$$$,$$9.99DB", "   $234.00DB", 2, 23400, true, nil },
+        PictureParseTC{ "$ZZ9-", "$ 42-", 0, 42, true, nil },
+        PictureParseTC{ "$ZZ9-", "# 42-", 0, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "---9", "  -5", 0, 5, true, nil },
+        PictureParseTC{ "+ZZ9", "+  5", 0, 5, false, nil },
+        PictureParseTC{ "+ZZ9", "*  5", 0, 0, false, strconv.ErrSyntax },
+        PictureParseTC{ "**,***.**", "******.01", 2, 1, false, nil },
+    }
+    for i, tc := range testCases {
+        p, err := CompilePicture(tc.pic)
+        if err!=nil {
+            t.Errorf("Compile error: %d: %v: %v", i, tc.pic, err)
+            continue
+        }
+        result, neg, err := p.ParseSigned(tc.value, tc.precision)
+        if tc.expected!=result || tc.expNeg!=neg || tc.expError!=err {
+            t.Errorf("Result mismatch: %d: parse(%v,%q,%v)->%v,%v,%v!=%v,%v,%v",
+                     i, tc.pic, tc.value, tc.precision, tc.expected, tc.expNeg,
+                     tc.expError, result, neg, err)
+        }
+        result, err = p.Parse(tc.value, tc.precision)
+        if tc.expNeg && err!=strconv.ErrRange {
+            t.Errorf("Error mismatch: %d: parse(%v,%q)->%v", i, tc.pic,
+                     tc.value, err)
+        }
+    }
+}
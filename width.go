@@ -0,0 +1,133 @@
+/*
+ * width.go - display width of strings
+ *
+ * godec128 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+// Package to operate on 64-bit decimal fixed point
+package godec64
+
+import (
+    "unicode"
+    "unicode/utf8"
+)
+
+// East Asian Wide (W) and Fullwidth (F) characters
+var eastAsianWide = &unicode.RangeTable{
+    R16: []unicode.Range16{
+        { 0x1100, 0x115f, 1 },
+        { 0x231a, 0x231b, 1 },
+        { 0x2329, 0x232a, 1 },
+        { 0x23e9, 0x23ec, 1 },
+        { 0x23f0, 0x23f0, 1 },
+        { 0x23f3, 0x23f3, 1 },
+        { 0x25fd, 0x25fe, 1 },
+        { 0x2614, 0x2615, 1 },
+        { 0x2648, 0x2653, 1 },
+        { 0x267f, 0x267f, 1 },
+        { 0x2693, 0x2693, 1 },
+        { 0x26a1, 0x26a1, 1 },
+        { 0x26aa, 0x26ab, 1 },
+        { 0x26bd, 0x26be, 1 },
+        { 0x26c4, 0x26c5, 1 },
+        { 0x26ce, 0x26ce, 1 },
+        { 0x26d4, 0x26d4, 1 },
+        { 0x26ea, 0x26ea, 1 },
+        { 0x26f2, 0x26f3, 1 },
+        { 0x26f5, 0x26f5, 1 },
+        { 0x26fa, 0x26fa, 1 },
+        { 0x26fd, 0x26fd, 1 },
+        { 0x2705, 0x2705, 1 },
+        { 0x270a, 0x270b, 1 },
+        { 0x2728, 0x2728, 1 },
+        { 0x274c, 0x274c, 1 },
+        { 0x274e, 0x274e, 1 },
+        { 0x2753, 0x2755, 1 },
+        { 0x2757, 0x2757, 1 },
+        { 0x2795, 0x2797, 1 },
+        { 0x27b0, 0x27b0, 1 },
+        { 0x27bf, 0x27bf, 1 },
+        { 0x2b1b, 0x2b1c, 1 },
+        { 0x2b50, 0x2b50, 1 },
+        { 0x2b55, 0x2b55, 1 },
+        { 0x2e80, 0x303e, 1 },
+        { 0x3041, 0x33ff, 1 },
+        { 0x3400, 0x4dbf, 1 },
+        { 0x4e00, 0x9fff, 1 },
+        { 0xa000, 0xa4cf, 1 },
+        { 0xa960, 0xa97f, 1 },
+        { 0xac00, 0xd7a3, 1 },
+        { 0xf900, 0xfaff, 1 },
+        { 0xfe10, 0xfe19, 1 },
+        { 0xfe30, 0xfe6f, 1 },
+        { 0xff00, 0xff60, 1 },
+        { 0xffe0, 0xffe6, 1 },
+    },
+    R32: []unicode.Range32{
+        { 0x16fe0, 0x16fe4, 1 },
+        { 0x17000, 0x18aff, 1 },
+        { 0x1b000, 0x1b2ff, 1 },
+        { 0x1f004, 0x1f004, 1 },
+        { 0x1f0cf, 0x1f0cf, 1 },
+        { 0x1f18e, 0x1f18e, 1 },
+        { 0x1f191, 0x1f19a, 1 },
+        { 0x1f200, 0x1f251, 1 },
+        { 0x1f300, 0x1f64f, 1 },
+        { 0x1f680, 0x1f6ff, 1 },
+        { 0x1f900, 0x1f9ff, 1 },
+        { 0x20000, 0x2fffd, 1 },
+        { 0x30000, 0x3fffd, 1 },
+    },
+}
+
+// get display width of rune: 0 for combining marks and format characters,
+// 2 for East Asian wide and fullwidth characters, otherwise 1
+func RuneWidth(r rune) int {
+    if r < 0x300 {
+        if r < 0x20 || (r>=0x7f && r<0xa0) { return 0 }
+        if r==0xad { return 0 } // soft hyphen
+        return 1
+    }
+    if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+        return 0
+    }
+    if unicode.Is(eastAsianWide, r) {
+        return 2
+    }
+    return 1
+}
+
+// get display width of string
+func StringWidth(s string) int {
+    w := 0
+    for _, r := range s {
+        w += RuneWidth(r)
+    }
+    return w
+}
+
+// get display width of bytes (UTF-8)
+func BytesWidth(s []byte) int {
+    w := 0
+    for len(s)>0 {
+        r, size := utf8.DecodeRune(s)
+        w += RuneWidth(r)
+        s = s[size:]
+    }
+    return w
+}
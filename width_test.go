@@ -0,0 +1,60 @@
+/*
+ * width_test.go - display width of strings tests
+ *
+ * godec64 - go dec64 (for 64-bit decimal fixed point) library
+ * Copyright (C) 2021  Mateusz Szpakowski
+ *
+ * This library is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU Lesser General Public
+ * License as published by the Free Software Foundation; either
+ * version 2.1 of the License, or (at your option) any later version.
+ *
+ * This library is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+ * Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public
+ * License along with this library; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+
+package godec64
+
+import (
+    "testing"
+)
+
+type StringWidthTC struct {
+    str string
+    expected int
+}
+
+func TestStringWidth(t *testing.T) {
+    testCases := []StringWidthTC {
+        StringWidthTC{ "", 0 },
+        StringWidthTC{ "1,234.56", 8 },
+        StringWidthTC{ "1 234,56", 8 },
+        StringWidthTC{ "١٬٢٣٤٫٥٦", 8 },
+        StringWidthTC{ "১,২৩৪.৫৬", 8 },
+        StringWidthTC{ "၁,၂၃၄.၅၆", 8 },
+        StringWidthTC{ "１２３", 6 },
+        StringWidthTC{ "¥1,234", 6 },
+        StringWidthTC{ "￥1,234", 7 },
+        StringWidthTC{ "円", 2 },
+        StringWidthTC{ "‏1.5", 3 },
+        StringWidthTC{ "é", 1 },
+    }
+    for i, tc := range testCases {
+        result := StringWidth(tc.str)
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: width(%q)->%v!=%v",
+                     i, tc.str, tc.expected, result)
+        }
+        result = BytesWidth([]byte(tc.str))
+        if tc.expected!=result {
+            t.Errorf("Result mismatch: %d: widthBytes(%q)->%v!=%v",
+                     i, tc.str, tc.expected, result)
+        }
+    }
+}